@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type command func(ctx context.Context, args []string, logger *zap.Logger) error
+
+var (
+	commands = map[string]command{
+		"erase":  runErase,
+		"export": runExport,
+		"import": runImport,
+		"seed":   runSeed,
+	}
+
+	errUnknownCommand = errors.New("unknown command")
+)
+
+func runCommand(ctx context.Context, name string, args []string, logger *zap.Logger) error {
+	cmd, inMap := commands[name]
+	if !inMap {
+		names := slices.Sorted(maps.Keys(commands))
+		return fmt.Errorf("%w %q, available: %s", errUnknownCommand, name, strings.Join(names, ", "))
+	}
+
+	err := cmd(ctx, args, logger)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+func connectDB(ctx context.Context) (*pgxpool.Pool, error) {
+	db, err := pgxpool.New(ctx, os.Getenv(dbConnStr))
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.New: %w", err)
+	}
+
+	if err := db.Ping(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("db.Ping: %w", err)
+	}
+
+	return db, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
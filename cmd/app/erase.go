@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	eraseAPIKey  = "ERASE_API_KEY"
+	eraseTimeout = time.Minute
+)
+
+var (
+	errEraseSubject = errors.New("exactly one of -customer and -order is required")
+	errEraseAPIKey  = errors.New("-api_key or " + eraseAPIKey + " is required")
+)
+
+// runErase goes through the admin API of a running server, which evicts its
+// cached copies:
+//
+//	app erase -addr http://localhost:8081 -customer c-42 -reason "ticket 1234"
+//	app erase -order b563feb7b2b84b6test
+//
+// The API key needs the orders:erase scope and is recorded as the requester.
+func runErase(ctx context.Context, args []string, logger *zap.Logger) error {
+	fs := newFlagSet("erase")
+	addr := fs.String("addr", "http://"+defaultAddr, "base URL of the server")
+	apiKey := fs.String("api_key", os.Getenv(eraseAPIKey), "API key with the orders:erase scope, default: $"+eraseAPIKey)
+	customerID := fs.String("customer", "", "customer ID whose orders are anonymised")
+	orderUID := fs.String("order", "", "order UID to delete")
+	reason := fs.String("reason", "", "why the erasure was requested, recorded in the audit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*customerID == "") == (*orderUID == "") {
+		return errEraseSubject
+	}
+	if *apiKey == "" {
+		return errEraseAPIKey
+	}
+
+	method, path := http.MethodDelete, "/admin/orders/"+url.PathEscape(*orderUID)
+	if *customerID != "" {
+		if !domain.ValidCustomerID(*customerID) {
+			return fmt.Errorf("invalid customer %q", *customerID)
+		}
+		method, path = http.MethodPost, "/admin/customers/"+url.PathEscape(*customerID)+"/anonymize"
+	} else if !domain.ValidOrderUID(*orderUID) {
+		return fmt.Errorf("invalid order %q", *orderUID)
+	}
+
+	target, err := url.JoinPath(*addr, path)
+	if err != nil {
+		return fmt.Errorf("url.JoinPath: %w", err)
+	}
+	target += "?" + url.Values{"reason": {*reason}}.Encode()
+
+	result, err := erase(ctx, method, target, *apiKey)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("erasure completed", zap.Int64("auditID", result.AuditID), zap.String("action", result.Action),
+		zap.Int("orders", result.Orders))
+	return nil
+}
+
+func erase(ctx context.Context, method, target, apiKey string) (*appHttp.ErasureResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, eraseTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("X-API-Key", apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http.Client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		problem := appHttp.Problem{}
+		if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil || problem.Code == "" {
+			return nil, fmt.Errorf("%s %s: %s", method, target, resp.Status)
+		}
+		return nil, fmt.Errorf("%s %s: %s: %s %s", method, target, resp.Status, problem.Code, problem.Detail)
+	}
+
+	result := &appHttp.ErasureResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("json.Decode: %w", err)
+	}
+	return result, nil
+}
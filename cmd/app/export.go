@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/repository/order"
+	exportUsecase "github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/export"
+)
+
+const stdStream = "-"
+
+func runExport(ctx context.Context, args []string, logger *zap.Logger) (err error) {
+	fs := newFlagSet("export")
+	format := fs.String(export.ParamFormat, export.FormatNDJSON, "output format: ndjson or csv")
+	out := fs.String("out", stdStream, `output file, "-" for stdout`)
+	params := map[string]*string{}
+	for _, name := range []string{
+		export.ParamCustomerID, export.ParamDeliveryService, export.ParamDateFrom, export.ParamDateTo,
+	} {
+		params[name] = fs.String(name, "", "filter by "+name)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := export.ParseFilter(func(name string) string { return *params[name] })
+	if err != nil {
+		return err
+	}
+
+	var dst io.Writer = os.Stdout
+	if *out != stdStream {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("os.Create: %w", err)
+		}
+		defer func() {
+			if errClose := file.Close(); errClose != nil && err == nil {
+				err = fmt.Errorf("file.Close: %w", errClose)
+			}
+		}()
+		dst = file
+	}
+
+	buf := bufio.NewWriter(dst)
+	writer, err := export.NewWriter(*format, buf)
+	if err != nil {
+		return err
+	}
+
+	db, err := connectDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	count, err := exportUsecase.New(order.NewRepository(db)).ExportOrders(ctx, filter, writer)
+	if err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("bufio.Flush: %w", err)
+	}
+
+	logger.Info("orders exported", zap.String("format", *format), zap.String("out", *out),
+		zap.Int("count", count))
+	return nil
+}
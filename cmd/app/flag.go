@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/app"
 )
@@ -14,6 +15,24 @@ const (
 	defaultKafkaBrokerAddr = "localhost:9092"
 	defaultKafkaTopicName  = "wbtech-l0-topic"
 	defaultAddr            = "localhost:8081"
+	defaultGRPCAddr        = "localhost:9091"
+	defaultBatchGetLimit   = 500
+	defaultIdempotencyKeys = 100000
+	defaultBulkTimeout     = 2 * time.Minute
+	defaultRateLimit       = 50
+	defaultRateBurst       = 100
+	defaultRateClients     = 100000
+	defaultMaxInFlight     = 1000
+	defaultAccessLogSample = 1.0
+	defaultOrderCacheCtl   = "private, max-age=300"
+	defaultOrderBodyCache  = 10000
+	defaultCompressMinSize = 1024
+	defaultStreamReplay    = 1000
+	defaultStreamBuffer    = 64
+	defaultSummaryCache    = 10000
+	defaultSummaryTTL      = 5 * time.Minute
+	defaultRetention       = 30 * 24 * time.Hour
+	defaultPurgeInterval   = time.Hour
 
 	dbConnStr     = "DB_CONN"
 	cacheCapacity = "CACHE_CAPACITY"
@@ -25,6 +44,28 @@ func initOpts() {
 	flag.StringVar(&opts.KafkaBrokerAddr, "broker_addr", defaultKafkaBrokerAddr, fmt.Sprintf("kafka broker host and port, default: %q", defaultKafkaBrokerAddr))
 	flag.StringVar(&opts.KafkaTopicName, "topic_name", defaultKafkaTopicName, fmt.Sprintf("kafka topic's name, default: %q", defaultKafkaTopicName))
 	flag.StringVar(&opts.Addr, "addr", defaultAddr, fmt.Sprintf("server address, default: %q", defaultAddr))
+	flag.StringVar(&opts.GRPCAddr, "grpc_addr", defaultGRPCAddr, fmt.Sprintf("gRPC server address, empty disables gRPC, default: %q", defaultGRPCAddr))
+	flag.StringVar(&opts.TemplatesDir, "templates_dir", "", "directory with *.html.tmpl files overriding embedded HTML templates")
+	flag.StringVar(&opts.AuthConfigPath, "auth_config", "", "JSON file with API keys and JWT settings, auth is disabled if empty")
+	flag.IntVar(&opts.BatchGetLimit, "batch_get_limit", defaultBatchGetLimit, fmt.Sprintf("max order uids per batch-get request, default: %d", defaultBatchGetLimit))
+	flag.IntVar(&opts.IdempotencyKeys, "idempotency_keys", defaultIdempotencyKeys, fmt.Sprintf("max Idempotency-Key values remembered, 0 disables, default: %d", defaultIdempotencyKeys))
+	flag.DurationVar(&opts.BulkTimeout, "bulk_timeout", defaultBulkTimeout, fmt.Sprintf("time limit of a POST /orders:bulk request, default: %s", defaultBulkTimeout))
+	flag.Float64Var(&opts.RateLimit, "rate_limit", defaultRateLimit, fmt.Sprintf("requests per second per client (verified principal or IP), 0 disables, default: %d", defaultRateLimit))
+	flag.IntVar(&opts.RateBurst, "rate_burst", defaultRateBurst, fmt.Sprintf("rate limiter burst per client, default: %d", defaultRateBurst))
+	flag.IntVar(&opts.RateClients, "rate_limit_clients", defaultRateClients, fmt.Sprintf("max clients tracked by the rate limiter, default: %d", defaultRateClients))
+	flag.IntVar(&opts.MaxInFlight, "max_in_flight", defaultMaxInFlight, fmt.Sprintf("max concurrent requests, 0 disables, default: %d", defaultMaxInFlight))
+	flag.StringVar(&opts.OrderCacheControl, "order_cache_control", defaultOrderCacheCtl, fmt.Sprintf("Cache-Control of order responses, empty omits the header, default: %q", defaultOrderCacheCtl))
+	flag.IntVar(&opts.OrderBodyCache, "order_body_cache", defaultOrderBodyCache, fmt.Sprintf("serialized orders kept in memory, 0 disables, default: %d", defaultOrderBodyCache))
+	flag.IntVar(&opts.CompressMinSize, "compress_min_size", defaultCompressMinSize, fmt.Sprintf("min response size in bytes compressed with zstd, br or gzip, 0 disables, default: %d", defaultCompressMinSize))
+	flag.IntVar(&opts.StreamReplay, "stream_replay", defaultStreamReplay, fmt.Sprintf("latest orders kept for Last-Event-ID replay of the live feed, default: %d", defaultStreamReplay))
+	flag.IntVar(&opts.StreamBuffer, "stream_buffer", defaultStreamBuffer, fmt.Sprintf("orders a live feed client may lag behind before it is dropped, default: %d", defaultStreamBuffer))
+	flag.IntVar(&opts.CustomerSummaryCache, "customer_summary_cache", defaultSummaryCache, fmt.Sprintf("customer order summaries kept in memory, 0 disables, default: %d", defaultSummaryCache))
+	flag.DurationVar(&opts.CustomerSummaryTTL, "customer_summary_ttl", defaultSummaryTTL, fmt.Sprintf("max age of a cached customer order summary, default: %s", defaultSummaryTTL))
+	flag.DurationVar(&opts.DeletedRetention, "deleted_retention", defaultRetention, fmt.Sprintf("how long deleted orders are kept before they are purged, default: %s", defaultRetention))
+	flag.DurationVar(&opts.PurgeInterval, "purge_interval", defaultPurgeInterval, fmt.Sprintf("interval of purging deleted orders past retention, 0 disables, default: %s", defaultPurgeInterval))
+	flag.DurationVar(&opts.ReportsRefresh, "reports_refresh", 0, "interval of refreshing the precomputed report aggregates, 0 makes reports read the order tables")
+	flag.Float64Var(&opts.AccessLogSample, "access_log_sample", defaultAccessLogSample, fmt.Sprintf("share of 1xx-3xx responses written to the access log, 4xx and 5xx are always logged, default: %g", defaultAccessLogSample))
+	flag.StringVar(&opts.TrustedProxies, "trusted_proxies", "", "comma-separated CIDRs of proxies allowed to set X-Forwarded-For")
 	flag.Parse()
 
 	opts.DBConnStr = os.Getenv(dbConnStr)
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/importer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/repository/order"
+)
+
+const defaultImportBatchSize = 500
+
+type importCheckpoint struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+}
+
+var errCheckpointSource = errors.New("checkpoint belongs to another source")
+
+func runImport(ctx context.Context, args []string, logger *zap.Logger) error {
+	fs := newFlagSet("import")
+	in := fs.String("in", stdStream, `input NDJSON file, "-" for stdin, gzip is detected automatically`)
+	batchSize := fs.Int("batch_size", defaultImportBatchSize, "orders per insert transaction")
+	checkpointPath := fs.String("checkpoint", "", "file to store progress in and resume from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive, got %d", *batchSize)
+	}
+
+	skipLines, err := loadCheckpoint(*checkpointPath, *in)
+	if err != nil {
+		return err
+	}
+	if skipLines > 0 {
+		logger.Info("resuming import", zap.String("in", *in), zap.Int("line", skipLines))
+	}
+
+	src, closeSrc, err := openImportSource(*in)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := closeSrc(); err != nil {
+			logger.Error("close import source", zap.Error(err))
+		}
+	}()
+
+	db, err := connectDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	imp := importer.New(ingest.NewDecoder(), order.NewRepository(db), *batchSize, logger)
+	report, err := imp.Import(ctx, src, skipLines, func(line int) error {
+		return saveCheckpoint(*checkpointPath, importCheckpoint{Source: *in, Line: line})
+	})
+	printImportReport(os.Stdout, report)
+
+	return err
+}
+
+func openImportSource(path string) (io.Reader, func() error, error) {
+	var file io.ReadCloser = os.Stdin
+	if path != stdStream {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("os.Open: %w", err)
+		}
+		file = f
+	}
+
+	buf := bufio.NewReader(file)
+	magic, err := buf.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, errors.Join(fmt.Errorf("peek: %w", err), file.Close())
+	}
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return buf, file.Close, nil
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("gzip.NewReader: %w", err), file.Close())
+	}
+	return gz, func() error {
+		return errors.Join(gz.Close(), file.Close())
+	}, nil
+}
+
+func loadCheckpoint(path, source string) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	checkpoint := importCheckpoint{}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return 0, fmt.Errorf("checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Source != source {
+		return 0, fmt.Errorf("%w: %q", errCheckpointSource, checkpoint.Source)
+	}
+
+	return checkpoint.Line, nil
+}
+
+func saveCheckpoint(path string, checkpoint importCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func printImportReport(w io.Writer, report importer.Report) {
+	fmt.Fprintf(w, "accepted:   %d\nrejected:   %d\nduplicates: %d\n",
+		report.Accepted, report.Rejected, report.Duplicates)
+
+	for _, r := range report.Rejections {
+		if r.OrderUID != "" {
+			fmt.Fprintf(w, "rejected line %d (%s): %s\n", r.Line, r.OrderUID, r.Reason)
+			continue
+		}
+		fmt.Fprintf(w, "rejected line %d: %s\n", r.Line, r.Reason)
+	}
+	for _, uid := range report.DuplicateUIDs {
+		fmt.Fprintf(w, "duplicate: %s\n", uid)
+	}
+}
@@ -5,15 +5,22 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"go.uber.org/zap"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/app"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
 )
 
 func main() {
+	if err := redact.RegisterEncoder(); err != nil {
+		log.Fatal("{FATAL} ", err)
+	}
+
 	cfg := zap.NewProductionConfig()
+	cfg.Encoding = redact.EncoderName
 	cfg.OutputPaths = []string{"stdout"}
 	cfg.ErrorOutputPaths = []string{"stderr"}
 	cfg.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
@@ -30,6 +37,13 @@ func main() {
 
 	ctx := runSignalHandler(context.Background(), logger)
 
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(ctx, os.Args[1], os.Args[2:], logger); err != nil {
+			logger.Fatal("{FATAL} command "+os.Args[1], zap.Error(err))
+		}
+		return
+	}
+
 	initOpts()
 	app, err := app.NewApp(ctx, app.NewConfig(opts), logger)
 	if err != nil {
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/repository/order"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+const (
+	defaultSeedCount = 100
+	defaultSeed      = 1
+)
+
+func runSeed(ctx context.Context, args []string, logger *zap.Logger) error {
+	fs := newFlagSet("seed")
+	count := fs.Int("count", defaultSeedCount, "number of generated orders")
+	seed := fs.Uint64("seed", defaultSeed, "random seed, the same seed gives the same orders")
+	itemsMin := fs.Int("items_min", fixtures.DefaultItemsMin, "min items per order")
+	itemsMax := fs.Int("items_max", fixtures.DefaultItemsMax, "max items per order")
+	edgeCases := fs.Bool("edge_cases", true, "also insert canned edge-case orders")
+	batchSize := fs.Int("batch_size", defaultImportBatchSize, "orders per insert transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch_size must be positive, got %d", *batchSize)
+	}
+
+	orders := fixtures.New(*seed, fixtures.WithItemsRange(*itemsMin, *itemsMax)).Orders(*count)
+	if *edgeCases {
+		orders = append(orders, fixtures.EdgeCases()...)
+	}
+
+	decoder := ingest.NewDecoder()
+	for _, o := range orders {
+		if err := decoder.Validate(o); err != nil {
+			return fmt.Errorf("fixture %s is invalid: %w", o.OrderUID, err)
+		}
+	}
+
+	db, err := connectDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repo := order.NewRepository(db)
+	inserted, duplicates := 0, 0
+	for batch := range slices.Chunk(orders, *batchSize) {
+		dups, err := repo.AddOrders(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("repo.AddOrders: %w", err)
+		}
+		duplicates += len(dups)
+		inserted += len(batch) - len(dups)
+	}
+
+	logger.Info("database seeded", zap.Uint64("seed", *seed), zap.Int("inserted", inserted),
+		zap.Int("duplicates", duplicates))
+	return nil
+}
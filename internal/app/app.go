@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"sync"
 	"time"
@@ -11,19 +13,40 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
 	appConsumer "github.com/AndrejDubinin/wbtech-l0/internal/app/consumer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	appGraphQL "github.com/AndrejDubinin/wbtech-l0/internal/app/graphql"
 	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/openapi"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
 	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memorycustomersummary "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_customer_summary"
+	memoryidempotency "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_idempotency"
 	memoryorder "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order"
+	memoryorderbody "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order_body"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/hub"
 	"github.com/AndrejDubinin/wbtech-l0/internal/infra/kafka/consumer"
 	"github.com/AndrejDubinin/wbtech-l0/internal/infra/repository/order"
 	consumerMw "github.com/AndrejDubinin/wbtech-l0/internal/middleware/consumer"
 	httpMw "github.com/AndrejDubinin/wbtech-l0/internal/middleware/http"
 	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/cache/preload"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/customer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/erasure"
 	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/add"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/batchget"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/export"
 	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/get"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/lookup"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/query"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/search"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/report"
+	"github.com/AndrejDubinin/wbtech-l0/static"
 )
 
+const idempotencyKeyTTL = 24 * time.Hour
+
 type (
 	cons interface {
 		ConsumeTopic(ctx context.Context, handler consumer.Handler, wg *sync.WaitGroup) error
@@ -33,10 +56,36 @@ type (
 		AddOrder(ctx context.Context, order domain.Order) error
 		GetOrders(ctx context.Context, amount int64) ([]*domain.Order, error)
 		GetOrder(ctx context.Context, orderUID string) (*domain.Order, error)
+		GetOrdersByUIDs(ctx context.Context, orderUIDs []string) (map[string]*domain.Order, error)
+		ExportOrders(ctx context.Context, filter domain.OrderFilter, fn func(order *domain.Order) error) error
+		GetOrdersParts(ctx context.Context, orderUIDs []string, parts domain.OrderParts,
+		) (map[string]*domain.Order, error)
+		ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+			limit int, parts domain.OrderParts) ([]*domain.Order, error)
+		GetOrderItems(ctx context.Context, orderUID string, limit, offset int) ([]domain.Item, int, error)
+		SearchOrders(ctx context.Context, search domain.OrderSearch) ([]domain.OrderHit, error)
+		Report(ctx context.Context, kind domain.ReportKind, query domain.ReportQuery) (*domain.Report, error)
+		RefreshReports(ctx context.Context) error
+		GetCustomerOrders(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error)
+		GetCustomerSummary(ctx context.Context, customerID string) (*domain.CustomerSummary, error)
+		GetOrdersByKey(ctx context.Context, key domain.OrderKey, limit int) ([]*domain.Order, error)
+		AnonymizeCustomer(ctx context.Context, customerID, token string, request domain.ErasureRequest,
+		) (*domain.Erasure, error)
+		SoftDeleteOrder(ctx context.Context, orderUID string, request domain.ErasureRequest) (*domain.Erasure, error)
+		PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time, request domain.ErasureRequest,
+		) (*domain.Erasure, error)
 	}
 	orderCache interface {
 		Get(orderUID string) *domain.Order
 		Put(order *domain.Order)
+		GetByKey(key domain.OrderKey) ([]*domain.Order, bool)
+		PutByKey(key domain.OrderKey, orders []*domain.Order)
+		Delete(orderUID string)
+	}
+	summaryCache interface {
+		Get(customerID string) *domain.CustomerSummary
+		Put(summary *domain.CustomerSummary)
+		Delete(customerID string)
 	}
 	server interface {
 		ListenAndServe() error
@@ -51,18 +100,24 @@ type (
 		Warn(msg string, fields ...zap.Field)
 		Error(msg string, fields ...zap.Field)
 		Fatal(msg string, fields ...zap.Field)
+		With(fields ...zap.Field) *zap.Logger
 		Sync() error
 	}
 
 	App struct {
-		config   config
-		consumer cons
-		db       *pgxpool.Pool
-		storage  orderStorage
-		cache    orderCache
-		server   server
-		mux      mux
-		logger   logger
+		config    config
+		consumer  cons
+		db        *pgxpool.Pool
+		storage   orderStorage
+		cache     orderCache
+		summaries summaryCache
+		hub       *hub.Hub
+		server    server
+		mux       mux
+		auth      *httpMw.Authenticator
+		limiter   *ratelimit.Limiter
+		grpc      *grpcServer
+		logger    logger
 	}
 )
 
@@ -84,17 +139,34 @@ func NewApp(ctx context.Context, config config, logger *zap.Logger) (*App, error
 		return nil, err
 	}
 
+	authenticator, err := newAuthenticator(config.authConfigPath, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := httpMw.NewClientIPResolver(config.limits.trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	mux := http.NewServeMux()
-	handler := httpMw.AccessLogMiddleware(mux, logger)
-	handler = httpMw.PanicMiddleware(handler, logger)
+	var limiter *ratelimit.Limiter
+	if config.limits.rateLimit > 0 {
+		limiter = ratelimit.New(config.limits.rateLimit, config.limits.rateBurst, config.limits.rateClients)
+	}
+	handler := newHandler(mux, config, limiter, authenticator, ips, logger)
 
-	return &App{
-		config:   config,
-		consumer: cons,
-		db:       db,
-		storage:  order.NewRepository(db),
-		cache:    memoryorder.New(config.cacheCapacity),
-		mux:      mux,
+	app := &App{
+		config:    config,
+		consumer:  cons,
+		db:        db,
+		storage:   order.NewRepository(db),
+		cache:     memoryorder.New(config.cacheCapacity),
+		summaries: memorycustomersummary.New(config.customerSummaries.capacity, config.customerSummaries.ttl),
+		hub:       hub.New(config.stream.replay, config.stream.buffer),
+		mux:       mux,
+		auth:      authenticator,
+		limiter:   limiter,
 		server: &http.Server{
 			Addr:         config.addr,
 			Handler:      handler,
@@ -102,7 +174,44 @@ func NewApp(ctx context.Context, config config, logger *zap.Logger) (*App, error
 			WriteTimeout: 10 * time.Second,
 		},
 		logger: logger,
-	}, nil
+	}
+	if config.grpcAddr != "" {
+		app.grpc = app.newGRPCServer(logger)
+	}
+
+	return app, nil
+}
+
+func newHandler(mux http.Handler, config config, limiter *ratelimit.Limiter, authenticator *httpMw.Authenticator,
+	ips *httpMw.ClientIPResolver, logger *zap.Logger,
+) http.Handler {
+	handler := httpMw.RouteMiddleware(mux)
+	if limiter != nil {
+		handler = httpMw.RateLimitMiddleware(handler, limiter, authenticator, ips, logger)
+	}
+	if config.limits.maxInFlight > 0 {
+		handler = httpMw.InFlightLimitMiddleware(handler, config.limits.maxInFlight, logger)
+	}
+	if config.compressMinSize > 0 {
+		handler = httpMw.CompressMiddleware(handler, config.compressMinSize)
+	}
+	handler = httpMw.AccessLogMiddleware(handler, config.accessLogRate, logger)
+	handler = httpMw.PanicMiddleware(handler, logger)
+	return httpMw.RequestIDMiddleware(handler, logger)
+}
+
+func newAuthenticator(configPath string, logger *zap.Logger) (*httpMw.Authenticator, error) {
+	if configPath == "" {
+		logger.Warn("authentication is disabled, order data including PII is open to every client")
+		return httpMw.NewDisabledAuthenticator(), nil
+	}
+
+	authConfig, err := httpMw.LoadAuthConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpMw.NewAuthenticator(authConfig)
 }
 
 func (a *App) Run(ctx context.Context) error {
@@ -124,6 +233,25 @@ func (a *App) Run(ctx context.Context) error {
 		return err
 	}
 
+	if a.config.reportsRefresh > 0 {
+		wg.Add(1)
+		go a.refreshReports(ctx, wg)
+	}
+
+	if a.config.retention.purgeInterval > 0 {
+		wg.Add(1)
+		go a.purgeDeleted(ctx, wg)
+	}
+
+	if a.grpc != nil {
+		go func() {
+			a.logger.Info("Starting gRPC server", zap.String("address", a.config.grpcAddr))
+			if err := a.grpc.serve(a.config.grpcAddr); err != nil {
+				a.logger.Fatal("starting gRPC server", zap.Error(err))
+			}
+		}()
+	}
+
 	go func() {
 		a.logger.Info("Starting server", zap.String("address", a.config.addr))
 		err := a.ListenAndServe()
@@ -141,6 +269,9 @@ func (a *App) Close(ctx context.Context) error {
 	var errs []error
 
 	a.logger.Info("shutting down server")
+	// Live feed streams never end on their own, close them first so that
+	// Shutdown does not wait for them.
+	a.hub.Close()
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -148,6 +279,11 @@ func (a *App) Close(ctx context.Context) error {
 		errs = append(errs, fmt.Errorf("server.Shutdown: %w", err))
 	}
 
+	if a.grpc != nil {
+		a.logger.Info("shutting down gRPC server")
+		a.grpc.shutdown(ctx)
+	}
+
 	a.logger.Info("closing consumer")
 	if err := a.consumer.Close(); err != nil {
 		errs = append(errs, fmt.Errorf("consumer.Close: %w", err))
@@ -164,9 +300,55 @@ func (a *App) Close(ctx context.Context) error {
 	return nil
 }
 
+func (a *App) refreshReports(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	reports := report.New(a.storage, true)
+	ticker := time.NewTicker(a.config.reportsRefresh)
+	defer ticker.Stop()
+	for {
+		if err := reports.Refresh(ctx); err != nil && ctx.Err() == nil {
+			a.logger.Error("reportUsecase.Refresh", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *App) purgeDeleted(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	erasures := a.erasureUsecase()
+	ticker := time.NewTicker(a.config.retention.purgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := erasures.Purge(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			a.logger.Error("erasureUsecase.Purge", zap.Error(err))
+		case err == nil && len(purged.OrderUIDs) > 0:
+			a.logger.Info("deleted orders purged", zap.Int64("auditID", purged.ID),
+				zap.Int("orders", len(purged.OrderUIDs)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *App) erasureUsecase() *erasure.Usecase {
+	return erasure.New(a.storage, a.cache, a.summaries, a.hub, a.config.retention.deleted)
+}
+
 func (a *App) runConsumer(ctx context.Context, wg *sync.WaitGroup) error {
-	consumerHandler := appConsumer.NewHandler(add.New(a.storage, a.cache), a.logger)
+	consumerHandler := appConsumer.NewHandler(add.New(a.storage, a.cache, a.summaries, a.hub), a.logger)
 	consumerHandler = consumerMw.Panic(consumerHandler, a.logger)
+	consumerHandler = consumerMw.RequestID(consumerHandler, a.logger)
 
 	a.logger.Info("consumer reads topic", zap.String("topic", a.config.consumer.Topic))
 	err := a.consumer.ConsumeTopic(ctx, consumerHandler, wg)
@@ -178,9 +360,75 @@ func (a *App) runConsumer(ctx context.Context, wg *sync.WaitGroup) error {
 }
 
 func (a *App) ListenAndServe() error {
-	a.mux.Handle(a.config.path.index, appHttp.NewIndexHandler())
-	a.mux.Handle(a.config.path.orderItemGet, appHttp.NewGetOrderHandler(get.New(a.storage, a.cache),
-		a.config.path.orderItemGet, a.logger))
+	frontend, err := fs.Sub(static.Frontend, "frontend")
+	if err != nil {
+		return fmt.Errorf("fs.Sub: %w", err)
+	}
+	staticHandler, err := appHttp.NewStaticHandler(frontend)
+	if err != nil {
+		return fmt.Errorf("appHttp.NewStaticHandler: %w", err)
+	}
+
+	renderer, err := appHttp.NewHTMLRenderer(a.config.templatesDir)
+	if err != nil {
+		return fmt.Errorf("appHttp.NewHTMLRenderer: %w", err)
+	}
+
+	queryUsecase := query.New(a.storage, a.cache)
+	graphQLHandler, err := appGraphQL.NewHandler(queryUsecase, a.config.path.graphQL, a.logger)
+	if err != nil {
+		return fmt.Errorf("appGraphQL.NewHandler: %w", err)
+	}
+
+	decoder := ingest.NewDecoder()
+	idempotency := memoryidempotency.New(idempotencyKeyTTL, a.config.ingestion.idempotencyKeys)
+	addUsecase := add.New(a.storage, a.cache, a.summaries, a.hub)
+
+	a.mux.Handle(a.config.path.index, staticHandler)
+	a.mux.Handle(a.config.path.health, appHttp.NewIndexHandler())
+	a.mux.Handle(a.config.path.openAPI, appHttp.NewDocumentHandler("application/json", openapi.Spec))
+	a.mux.Handle(a.config.path.docs, appHttp.NewDocumentHandler("text/html; charset=utf-8", openapi.DocsPage))
+	a.handle(a.config.path.metrics, auth.ScopeMetricsRead, expvar.Handler())
+	a.handle(a.config.path.orderItemGet, auth.ScopeOrdersRead, appHttp.NewGetOrderHandler(
+		get.New(a.storage, a.cache), queryUsecase, renderer, memoryorderbody.New(a.config.orderCaching.bodies),
+		a.config.orderCaching.cacheControl, a.config.path.orderItemGet, a.logger))
+	a.handle(a.config.path.ordersAdd, auth.ScopeOrdersWrite, appHttp.NewAddOrderHandler(
+		addUsecase, decoder, idempotency, a.config.path.ordersAdd, a.logger))
+	a.handle(a.config.path.ordersAddBulk, auth.ScopeOrdersWrite, appHttp.NewAddOrdersBulkHandler(
+		addUsecase, decoder, idempotency, a.config.ingestion.bulkTimeout, a.config.path.ordersAddBulk, a.logger))
+	a.handle(a.config.path.ordersBatchGet, auth.ScopeOrdersRead, appHttp.NewBatchGetOrdersHandler(
+		batchget.New(a.storage, a.cache), a.config.batchGetLimit, a.config.path.ordersBatchGet, a.logger))
+	// Exports are not masked, so they need the PII scope.
+	a.handle(a.config.path.ordersExport, auth.ScopeOrdersReadPII, appHttp.NewExportOrdersHandler(
+		export.New(a.storage), a.config.path.ordersExport, a.logger))
+	a.handle(a.config.path.ordersSearch, auth.ScopeOrdersRead, appHttp.NewSearchOrdersHandler(
+		search.New(a.storage), a.config.path.ordersSearch, a.logger))
+	lookupUsecase := lookup.New(a.storage, a.cache)
+	a.handle(a.config.path.ordersByTrack, auth.ScopeOrdersRead, appHttp.NewLookupOrdersHandler(
+		lookupUsecase, domain.KeyTrackNumber, definitions.ParamTrackNumber, a.config.path.ordersByTrack, a.logger))
+	a.handle(a.config.path.ordersByTx, auth.ScopeOrdersRead, appHttp.NewLookupOrdersHandler(
+		lookupUsecase, domain.KeyTransaction, definitions.ParamTransaction, a.config.path.ordersByTx, a.logger))
+	a.handle(a.config.path.ordersStream, auth.ScopeOrdersRead, appHttp.NewOrderStreamHandler(
+		a.hub, a.config.path.ordersStream, a.logger))
+	a.handle(a.config.path.ordersStreamWS, auth.ScopeOrdersRead, appHttp.NewOrderWebSocketHandler(
+		a.hub, a.config.path.ordersStreamWS, a.logger))
+	a.handle(a.config.path.graphQL, auth.ScopeOrdersRead, graphQLHandler)
+	a.handle(a.config.path.customerOrders, auth.ScopeOrdersRead, appHttp.NewCustomerOrdersHandler(
+		customer.New(a.storage, a.summaries), a.config.path.customerOrders, a.logger))
+	a.handle(a.config.path.reports, auth.ScopeReportsRead, appHttp.NewReportHandler(
+		report.New(a.storage, a.config.reportsRefresh > 0), a.config.path.reports, a.logger))
+	// Erasures need an authenticated caller to audit.
+	if a.config.authConfigPath != "" {
+		erasureUsecase := a.erasureUsecase()
+		a.handle(a.config.path.anonymize, auth.ScopeOrdersErase, appHttp.NewAnonymizeCustomerHandler(
+			erasureUsecase, a.config.path.anonymize, a.logger))
+		a.handle(a.config.path.orderDelete, auth.ScopeOrdersErase, appHttp.NewDeleteOrderHandler(
+			erasureUsecase, a.config.path.orderDelete, a.logger))
+	}
 
 	return a.server.ListenAndServe()
 }
+
+func (a *App) handle(pattern, scope string, handler http.Handler) {
+	a.mux.Handle(pattern, httpMw.AuthMiddleware(handler, a.auth, scope, a.logger))
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+const (
+	ScopeOrdersRead    = "orders:read"
+	ScopeOrdersReadPII = "orders:read:pii"
+	ScopeOrdersWrite   = "orders:write"
+	ScopeReportsRead   = "reports:read"
+	ScopeOrdersErase   = "orders:erase"
+	ScopeMetricsRead   = "metrics:read"
+)
+
+type (
+	Principal struct {
+		Subject string
+		Method  string // "api_key", "jwt" or "anonymous"
+		Scopes  []string
+	}
+
+	principalKey struct{}
+)
+
+// Anonymous is used when authentication is disabled.
+var Anonymous = Principal{
+	Subject: "anonymous",
+	Method:  "anonymous",
+	Scopes:  []string{ScopeOrdersReadPII, ScopeOrdersWrite, ScopeReportsRead, ScopeMetricsRead},
+}
+
+// HasScope treats "orders:read:pii" as granting "orders:read".
+func (p Principal) HasScope(scope string) bool {
+	return slices.ContainsFunc(p.Scopes, func(granted string) bool {
+		return granted == scope || strings.HasPrefix(granted, scope+":")
+	})
+}
+
+func (p Principal) ID() string {
+	return p.Method + ":" + p.Subject
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
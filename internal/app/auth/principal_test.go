@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHasScope(t *testing.T) {
+	p := Principal{Scopes: []string{ScopeOrdersReadPII, ScopeReportsRead}}
+
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{ScopeOrdersReadPII, true},
+		{ScopeOrdersRead, true},
+		{ScopeReportsRead, true},
+		{ScopeOrdersWrite, false},
+		{ScopeOrdersErase, false},
+		{"orders:re", false},
+	}
+	for _, tt := range tests {
+		if got := p.HasScope(tt.scope); got != tt.want {
+			t.Errorf("HasScope(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+
+	if (Principal{Scopes: []string{ScopeOrdersRead}}).HasScope(ScopeOrdersReadPII) {
+		t.Error("orders:read grants orders:read:pii")
+	}
+}
+
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Fatal("principal in an empty context")
+	}
+
+	p := Principal{Subject: "support", Method: "api_key"}
+	got, ok := PrincipalFromContext(WithPrincipal(context.Background(), p))
+	if !ok || got.ID() != "api_key:support" {
+		t.Fatalf("principal %+v, %v", got, ok)
+	}
+}
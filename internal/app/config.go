@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
 	"github.com/AndrejDubinin/wbtech-l0/internal/infra/kafka"
@@ -10,20 +12,75 @@ import (
 
 type (
 	Options struct {
-		KafkaBrokerAddr, KafkaTopicName, DBConnStr, Addr string
-		CacheCapacity                                    int64
+		KafkaBrokerAddr, KafkaTopicName, DBConnStr, Addr   string
+		GRPCAddr                                           string
+		TemplatesDir, AuthConfigPath                       string
+		TrustedProxies, OrderCacheControl                  string
+		CacheCapacity                                      int64
+		BatchGetLimit, RateBurst, RateClients, MaxInFlight int
+		OrderBodyCache, CompressMinSize                    int
+		StreamReplay, StreamBuffer                         int
+		CustomerSummaryCache, IdempotencyKeys              int
+		RateLimit, AccessLogSample                         float64
+		ReportsRefresh, CustomerSummaryTTL                 time.Duration
+		DeletedRetention, PurgeInterval, BulkTimeout       time.Duration
+	}
+	limits struct {
+		rateLimit      float64
+		rateBurst      int
+		rateClients    int
+		maxInFlight    int
+		trustedProxies []string
+	}
+	ingestion struct {
+		idempotencyKeys int
+		bulkTimeout     time.Duration
+	}
+	orderCaching struct {
+		cacheControl string
+		bodies       int
+	}
+	stream struct {
+		replay int
+		buffer int
+	}
+	customerSummaries struct {
+		capacity int
+		ttl      time.Duration
+	}
+	retention struct {
+		deleted       time.Duration
+		purgeInterval time.Duration
 	}
 	path struct {
-		index, orderItemGet string
+		index, health, metrics, orderItemGet, ordersBatchGet string
+		ordersAdd, ordersAddBulk, ordersExport, ordersSearch string
+		ordersStream, ordersStreamWS, graphQL, reports       string
+		customerOrders, ordersByTrack, ordersByTx            string
+		anonymize, orderDelete                               string
+		openAPI, docs                                        string
 	}
 
 	config struct {
-		kafka         kafka.Config
-		consumer      consumer.Config
-		dbConnStr     string
-		cacheCapacity int64
-		addr          string
-		path          path
+		kafka             kafka.Config
+		consumer          consumer.Config
+		dbConnStr         string
+		cacheCapacity     int64
+		addr              string
+		grpcAddr          string
+		templatesDir      string
+		authConfigPath    string
+		batchGetLimit     int
+		accessLogRate     float64
+		compressMinSize   int
+		reportsRefresh    time.Duration
+		ingestion         ingestion
+		orderCaching      orderCaching
+		customerSummaries customerSummaries
+		retention         retention
+		stream            stream
+		limits            limits
+		path              path
 	}
 )
 
@@ -37,12 +94,64 @@ func NewConfig(opts Options) config {
 		consumer: consumer.Config{
 			Topic: opts.KafkaTopicName,
 		},
-		dbConnStr:     opts.DBConnStr,
-		cacheCapacity: opts.CacheCapacity,
-		addr:          opts.Addr,
+		dbConnStr:       opts.DBConnStr,
+		cacheCapacity:   opts.CacheCapacity,
+		addr:            opts.Addr,
+		grpcAddr:        opts.GRPCAddr,
+		templatesDir:    opts.TemplatesDir,
+		authConfigPath:  opts.AuthConfigPath,
+		batchGetLimit:   opts.BatchGetLimit,
+		accessLogRate:   opts.AccessLogSample,
+		compressMinSize: opts.CompressMinSize,
+		reportsRefresh:  opts.ReportsRefresh,
+		ingestion: ingestion{
+			idempotencyKeys: opts.IdempotencyKeys,
+			bulkTimeout:     opts.BulkTimeout,
+		},
+		orderCaching: orderCaching{
+			cacheControl: opts.OrderCacheControl,
+			bodies:       opts.OrderBodyCache,
+		},
+		customerSummaries: customerSummaries{
+			capacity: opts.CustomerSummaryCache,
+			ttl:      opts.CustomerSummaryTTL,
+		},
+		retention: retention{
+			deleted:       opts.DeletedRetention,
+			purgeInterval: opts.PurgeInterval,
+		},
+		stream: stream{
+			replay: opts.StreamReplay,
+			buffer: opts.StreamBuffer,
+		},
+		limits: limits{
+			rateLimit:      opts.RateLimit,
+			rateBurst:      opts.RateBurst,
+			rateClients:    opts.RateClients,
+			maxInFlight:    opts.MaxInFlight,
+			trustedProxies: strings.Split(opts.TrustedProxies, ","),
+		},
 		path: path{
-			index:        "/",
-			orderItemGet: fmt.Sprintf("/order/{%s}", definitions.ParamOrderUID),
+			index:          "/",
+			health:         "/health",
+			metrics:        "/debug/vars",
+			orderItemGet:   fmt.Sprintf("/order/{%s}", definitions.ParamOrderUID),
+			ordersBatchGet: "POST /orders/batch-get",
+			ordersAdd:      "POST /orders",
+			ordersAddBulk:  "POST /orders:bulk",
+			ordersExport:   "GET /orders/export",
+			ordersSearch:   "GET /orders/search",
+			ordersStream:   "GET /orders/stream",
+			ordersStreamWS: "GET /orders/stream/ws",
+			graphQL:        "POST /graphql",
+			reports:        fmt.Sprintf("GET /reports/{%s}", definitions.ParamReport),
+			customerOrders: fmt.Sprintf("GET /customers/{%s}/orders", definitions.ParamCustomerID),
+			ordersByTrack:  fmt.Sprintf("GET /orders/by-track/{%s}", definitions.ParamTrackNumber),
+			ordersByTx:     fmt.Sprintf("GET /orders/by-transaction/{%s}", definitions.ParamTransaction),
+			anonymize:      fmt.Sprintf("POST /admin/customers/{%s}/anonymize", definitions.ParamCustomerID),
+			orderDelete:    fmt.Sprintf("DELETE /admin/orders/{%s}", definitions.ParamOrderUID),
+			openAPI:        "GET /openapi.json",
+			docs:           "GET /docs",
 		},
 	}
 }
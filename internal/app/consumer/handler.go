@@ -2,13 +2,13 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/IBM/sarama"
-	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
 	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
 )
 
 type (
@@ -21,7 +21,7 @@ type (
 	}
 
 	Handler struct {
-		validate        *validator.Validate
+		decoder         *ingest.Decoder
 		ServeMsgFn      func(context.Context, *sarama.ConsumerMessage)
 		addOrderUsecase addOrderUsecase
 		logger          logger
@@ -30,7 +30,7 @@ type (
 
 func NewHandler(usecase addOrderUsecase, logger logger) *Handler {
 	handler := &Handler{
-		validate:        validator.New(validator.WithRequiredStructEnabled()),
+		decoder:         ingest.NewDecoder(),
 		addOrderUsecase: usecase,
 		logger:          logger,
 	}
@@ -44,19 +44,21 @@ func (h *Handler) ServeMsg(ctx context.Context, s *sarama.ConsumerMessage) {
 }
 
 func (h *Handler) serveMsg(ctx context.Context, s *sarama.ConsumerMessage) {
-	order := domain.Order{}
-	if err := json.Unmarshal(s.Value, &order); err != nil {
-		h.logger.Error("json.unmarshal", zap.Error(err))
+	order, err := h.decoder.Decode(s.Value)
+	if err != nil {
+		h.loggerFor(ctx).Error("decoder.Decode", zap.Error(err))
 		return
 	}
 
-	if err := h.validate.Struct(order); err != nil {
-		h.logger.Error("validation", zap.Error(err))
-		return
+	err = h.addOrderUsecase.AddOrder(ctx, order)
+	if err != nil {
+		h.loggerFor(ctx).Error("usecase", zap.Error(err))
 	}
+}
 
-	err := h.addOrderUsecase.AddOrder(ctx, order)
-	if err != nil {
-		h.logger.Error("usecase", zap.Error(err))
+func (h *Handler) loggerFor(ctx context.Context) logger {
+	if l, ok := logctx.Logger(ctx); ok {
+		return l
 	}
+	return h.logger
 }
@@ -1,5 +1,11 @@
 package definitions
 
 const (
-	ParamOrderUID = "order_uid"
+	ParamOrderUID    = "order_uid"
+	ParamReport      = "report"
+	ParamCustomerID  = "customer_id"
+	ParamTrackNumber = "track_number"
+	ParamTransaction = "transaction"
+
+	HeaderRequestID = "X-Request-ID"
 )
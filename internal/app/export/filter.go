@@ -0,0 +1,53 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	ParamFormat          = "format"
+	ParamCustomerID      = "customer_id"
+	ParamDeliveryService = "delivery_service"
+	ParamDateFrom        = "date_from"
+	ParamDateTo          = "date_to"
+
+	dateLayout = time.DateOnly
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ParseFilter accepts RFC 3339 or YYYY-MM-DD dates; date_to is exclusive.
+func ParseFilter(get func(name string) string) (domain.OrderFilter, error) {
+	return ParseFilterIn(get, time.UTC)
+}
+
+func ParseFilterIn(get func(name string) string, loc *time.Location) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		CustomerID:      get(ParamCustomerID),
+		DeliveryService: get(ParamDeliveryService),
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDate(get(ParamDateFrom), loc); err != nil {
+		return domain.OrderFilter{}, fmt.Errorf("%w: %s: %w", ErrInvalidFilter, ParamDateFrom, err)
+	}
+	if filter.CreatedTo, err = parseDate(get(ParamDateTo), loc); err != nil {
+		return domain.OrderFilter{}, fmt.Errorf("%w: %s: %w", ErrInvalidFilter, ParamDateTo, err)
+	}
+
+	return filter, nil
+}
+
+func parseDate(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(dateLayout, value, loc)
+}
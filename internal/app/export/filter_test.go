@@ -0,0 +1,63 @@
+package export
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	params := map[string]string{
+		ParamCustomerID:      "c-1",
+		ParamDeliveryService: "meest",
+		ParamDateFrom:        "2025-01-02",
+		ParamDateTo:          "2025-01-03T10:00:00+03:00",
+	}
+	filter, err := ParseFilter(func(name string) string { return params[name] })
+	if err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+
+	if filter.CustomerID != "c-1" || filter.DeliveryService != "meest" {
+		t.Errorf("filter %+v", filter)
+	}
+	if want := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC); !filter.CreatedFrom.Equal(want) {
+		t.Errorf("CreatedFrom %v, want %v", filter.CreatedFrom, want)
+	}
+	if want := time.Date(2025, 1, 3, 7, 0, 0, 0, time.UTC); !filter.CreatedTo.Equal(want) {
+		t.Errorf("CreatedTo %v, want %v", filter.CreatedTo, want)
+	}
+}
+
+func TestParseFilterIn(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	filter, err := ParseFilterIn(func(name string) string {
+		if name == ParamDateFrom {
+			return "2025-01-02"
+		}
+		return ""
+	}, moscow)
+	if err != nil {
+		t.Fatalf("ParseFilterIn: %v", err)
+	}
+	if want := time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC); !filter.CreatedFrom.Equal(want) {
+		t.Errorf("CreatedFrom %v, want %v", filter.CreatedFrom, want)
+	}
+	if !filter.CreatedTo.IsZero() {
+		t.Errorf("CreatedTo %v, want zero", filter.CreatedTo)
+	}
+}
+
+func TestParseFilterInvalidDate(t *testing.T) {
+	for _, name := range []string{ParamDateFrom, ParamDateTo} {
+		_, err := ParseFilter(func(param string) string {
+			if param == name {
+				return "02.01.2025"
+			}
+			return ""
+		})
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: error %v, want ErrInvalidFilter", name, err)
+		}
+	}
+}
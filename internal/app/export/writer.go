@@ -0,0 +1,129 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+type (
+	Writer interface {
+		domain.OrderWriter
+		ContentType() string
+	}
+
+	ndjsonWriter struct {
+		enc *json.Encoder
+	}
+	csvWriter struct {
+		w             *csv.Writer
+		headerWritten bool
+	}
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var csvHeader = []string{
+	"order_uid", "track_number", "entry", "locale", "internal_signature", "customer_id",
+	"delivery_service", "shardkey", "sm_id", "date_created", "oof_shard",
+
+	"delivery_name", "delivery_phone", "delivery_zip", "delivery_city", "delivery_address",
+	"delivery_region", "delivery_email",
+
+	"payment_transaction", "payment_request_id", "payment_currency", "payment_provider",
+	"payment_amount", "payment_dt", "payment_bank", "payment_delivery_cost", "payment_goods_total",
+	"payment_custom_fee",
+
+	"item_chrt_id", "item_track_number", "item_price", "item_rid", "item_name", "item_sale",
+	"item_size", "item_total_price", "item_nm_id", "item_brand", "item_status",
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatNDJSON, "":
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func (w *ndjsonWriter) Write(order *domain.Order) error {
+	return w.enc.Encode(order)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func (w *ndjsonWriter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// Write emits one row per item.
+func (w *csvWriter) Write(order *domain.Order) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	head := orderColumns(order)
+	for _, item := range order.Items {
+		if err := w.w.Write(append(head, itemColumns(item)...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *csvWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *csvWriter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func orderColumns(o *domain.Order) []string {
+	d, p := o.Delivery, o.Payment
+	return []string{
+		o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.InternalSignature, o.CustomerID,
+		o.DeliveryService, o.ShardKey, strconv.Itoa(o.SmID), o.DateCreated.UTC().Format(time.RFC3339),
+		o.OofShard,
+
+		d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email,
+
+		p.Transaction, p.RequestID, p.Currency, p.Provider, strconv.Itoa(p.Amount),
+		strconv.FormatInt(p.PaymentDT, 10), p.Bank, strconv.Itoa(p.DeliveryCost),
+		strconv.Itoa(p.GoodsTotal), strconv.Itoa(p.CustomFee),
+	}
+}
+
+func itemColumns(it domain.Item) []string {
+	return []string{
+		strconv.Itoa(it.ChrtID), it.TrackNumber, strconv.Itoa(it.Price), it.RID, it.Name,
+		strconv.Itoa(it.Sale), it.Size, strconv.Itoa(it.TotalPrice), strconv.Itoa(it.NmID), it.Brand,
+		strconv.Itoa(it.Status),
+	}
+}
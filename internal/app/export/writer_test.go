@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	orders := fixtures.New(1).Orders(3)
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(FormatNDJSON, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range orders {
+		if err := w.Write(&orders[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(nil, 1<<20)
+	for i := 0; scanner.Scan(); i++ {
+		var order domain.Order
+		if err := json.Unmarshal(scanner.Bytes(), &order); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if order.OrderUID != orders[i].OrderUID {
+			t.Errorf("line %d: order %s, want %s", i+1, order.OrderUID, orders[i].OrderUID)
+		}
+	}
+	if w.ContentType() != "application/x-ndjson" {
+		t.Errorf("ContentType %q", w.ContentType())
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	orders := fixtures.New(1, fixtures.WithItemsRange(2, 2)).Orders(2)
+	orders[0].Delivery.Address = `Lenina, 1 "A"`
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(FormatCSV, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range orders {
+		if err := w.Write(&orders[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("%d rows, want a header and a row per item", len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != len(csvHeader) {
+			t.Fatalf("row of %d columns, want %d", len(row), len(csvHeader))
+		}
+	}
+	if rows[1][0] != orders[0].OrderUID || rows[2][0] != orders[0].OrderUID || rows[3][0] != orders[1].OrderUID {
+		t.Errorf("order columns are not repeated per item: %v", rows)
+	}
+	if rows[1][15] != orders[0].Delivery.Address {
+		t.Errorf("address %q, want %q", rows[1][15], orders[0].Delivery.Address)
+	}
+	if rows[2][len(csvHeader)-7] != orders[0].Items[1].Name {
+		t.Errorf("item name %q, want %q", rows[2][len(csvHeader)-7], orders[0].Items[1].Name)
+	}
+}
+
+func TestCSVWriterEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(FormatCSV, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("rows %v, %v; want only the header", rows, err)
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := NewWriter("xml", &bytes.Buffer{}); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("error %v, want ErrUnknownFormat", err)
+	}
+}
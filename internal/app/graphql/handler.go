@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+)
+
+const (
+	maxBodyBytes = 1 << 20
+	maxDepth     = 8
+)
+
+type (
+	Request struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName,omitempty"`
+		Variables     map[string]any `json:"variables,omitempty"`
+	}
+
+	Handler struct {
+		name   string
+		schema *graphqlgo.Schema
+		logger logger
+	}
+)
+
+func NewHandler(usecase queryUsecase, name string, logger logger) (*Handler, error) {
+	schema, err := graphqlgo.ParseSchema(Schema, &resolver{usecase: usecase, logger: logger},
+		graphqlgo.UseStringDescriptions(),
+		graphqlgo.MaxDepth(maxDepth),
+		graphqlgo.Logger(panicLogger{logger: logger}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("graphql.ParseSchema: %w", err)
+	}
+
+	return &Handler{
+		name:   name,
+		schema: schema,
+		logger: logger,
+	}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			appHttp.WriteProblem(w, r, appHttp.ErrBodyTooLarge, fmt.Sprintf("max %d bytes", maxBodyBytes))
+			return
+		}
+		appHttp.WriteProblem(w, r, appHttp.ErrInvalidBody, err.Error())
+		return
+	}
+	if req.Query == "" {
+		appHttp.WriteProblem(w, r, appHttp.ErrInvalidBody, "query is required")
+		return
+	}
+
+	response := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	body, err := json.Marshal(response)
+	if err != nil {
+		loggerFrom(r.Context(), h.logger).Error("json.Marshal", zap.Error(err))
+		appHttp.WriteProblem(w, r, appHttp.ErrInternalServerError, "")
+		return
+	}
+
+	appHttp.GetSuccessResponseWithBody(w, body)
+}
+
+type panicLogger struct {
+	logger logger
+}
+
+func (l panicLogger) LogPanic(ctx context.Context, value any) {
+	loggerFrom(ctx, l.logger).Error("graphql resolver panic", zap.Any("panic", value),
+		zap.StackSkip("stack", 1))
+}
@@ -0,0 +1,290 @@
+package graphql
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeQueryUsecase struct {
+	orders []*domain.Order // newest first
+	parts  domain.OrderParts
+	filter domain.OrderFilter
+	after  *domain.OrderCursor
+	limit  int
+}
+
+func newFakeQueryUsecase(n int) *fakeQueryUsecase {
+	u := &fakeQueryUsecase{}
+	for _, order := range fixtures.New(9).Orders(n) {
+		u.orders = append(u.orders, &order)
+	}
+	slices.SortFunc(u.orders, func(a, b *domain.Order) int {
+		return cmp.Or(b.DateCreated.Compare(a.DateCreated), strings.Compare(b.OrderUID, a.OrderUID))
+	})
+	return u
+}
+
+func (u *fakeQueryUsecase) GetOrder(_ context.Context, orderUID string, parts domain.OrderParts,
+) (*domain.Order, error) {
+	u.parts = parts
+	for _, order := range u.orders {
+		if order.OrderUID == orderUID {
+			return order, nil
+		}
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+func (u *fakeQueryUsecase) ListOrders(_ context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+	limit int, parts domain.OrderParts,
+) ([]*domain.Order, error) {
+	u.filter, u.after, u.limit, u.parts = filter, after, limit, parts
+	start := 0
+	if after != nil {
+		start = slices.IndexFunc(u.orders, func(order *domain.Order) bool {
+			return order.OrderUID == after.OrderUID
+		}) + 1
+	}
+	return u.orders[start:min(start+limit, len(u.orders))], nil
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, h *Handler, principal *auth.Principal, query string, variables map[string]any,
+) graphQLResponse {
+	t.Helper()
+	body, err := json.Marshal(Request{Query: query, Variables: variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	if principal != nil {
+		r = r.WithContext(auth.WithPrincipal(r.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %s: %v", rec.Body, err)
+	}
+	return response
+}
+
+func newTestHandler(t *testing.T, u *fakeQueryUsecase) *Handler {
+	t.Helper()
+	h, err := NewHandler(u, "graphql", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestOrderLoadsSelectedParts(t *testing.T) {
+	u := newFakeQueryUsecase(1)
+	h := newTestHandler(t, u)
+	order := u.orders[0]
+
+	tests := []struct {
+		query string
+		want  domain.OrderParts
+	}{
+		{`query($uid: String!) { order(uid: $uid) { orderUid } }`, domain.OrderParts{}},
+		{`query($uid: String!) { order(uid: $uid) { delivery { city } } }`, domain.OrderParts{Delivery: true}},
+		{`query($uid: String!) { order(uid: $uid) { payment { amount } items { name } } }`,
+			domain.OrderParts{Payment: true, Items: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			response := execute(t, h, &auth.Anonymous, tt.query, map[string]any{"uid": order.OrderUID})
+			if len(response.Errors) > 0 {
+				t.Fatalf("errors %+v", response.Errors)
+			}
+			if u.parts != tt.want {
+				t.Errorf("parts %+v, want %+v", u.parts, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderMasksPII(t *testing.T) {
+	u := newFakeQueryUsecase(1)
+	h := newTestHandler(t, u)
+	order := u.orders[0]
+	query := `query($uid: String!) { order(uid: $uid) { delivery { phone } } }`
+	variables := map[string]any{"uid": order.OrderUID}
+
+	if response := execute(t, h, &auth.Anonymous, query, variables); !strings.Contains(
+		string(response.Data["order"]), order.Delivery.Phone) {
+		t.Errorf("order %s, want the phone with orders:read:pii", response.Data["order"])
+	}
+	reader := auth.Principal{Subject: "support", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}}
+	if response := execute(t, h, &reader, query, variables); strings.Contains(
+		string(response.Data["order"]), order.Delivery.Phone) {
+		t.Errorf("order %s, want the phone masked", response.Data["order"])
+	}
+}
+
+func TestOrderErrors(t *testing.T) {
+	h := newTestHandler(t, newFakeQueryUsecase(1))
+	query := `query($uid: String!) { order(uid: $uid) { orderUid } }`
+
+	response := execute(t, h, nil, query, map[string]any{"uid": "missing-order-0000"})
+	if len(response.Errors) > 0 || string(response.Data["order"]) != "null" {
+		t.Errorf("missing order: data %s, errors %+v", response.Data["order"], response.Errors)
+	}
+
+	response = execute(t, h, nil, query, map[string]any{"uid": "bad uid"})
+	if len(response.Errors) != 1 || response.Errors[0].Message != errInvalidUID.Error() {
+		t.Errorf("invalid uid: errors %+v", response.Errors)
+	}
+}
+
+func TestOrdersPagination(t *testing.T) {
+	u := newFakeQueryUsecase(5)
+	h := newTestHandler(t, u)
+	query := `query($after: String) {
+		orders(filter: {customerId: "c-1"}, first: 2, after: $after) {
+			edges { cursor node { orderUid } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	var (
+		seen  []string
+		after any
+	)
+	for page := 0; ; page++ {
+		response := execute(t, h, nil, query, map[string]any{"after": after})
+		if len(response.Errors) > 0 {
+			t.Fatalf("page %d: errors %+v", page, response.Errors)
+		}
+		var orders struct {
+			Edges []struct {
+				Cursor string
+				Node   struct{ OrderUID string }
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+		if err := json.Unmarshal(response.Data["orders"], &orders); err != nil {
+			t.Fatal(err)
+		}
+		if u.limit != 3 || u.filter.CustomerID != "c-1" || u.parts != (domain.OrderParts{}) {
+			t.Errorf("page %d: limit %d, filter %+v, parts %+v", page, u.limit, u.filter, u.parts)
+		}
+		for _, edge := range orders.Edges {
+			seen = append(seen, edge.Node.OrderUID)
+		}
+		if !orders.PageInfo.HasNextPage {
+			break
+		}
+		after = *orders.PageInfo.EndCursor
+	}
+
+	want := make([]string, 0, len(u.orders))
+	for _, order := range u.orders {
+		want = append(want, order.OrderUID)
+	}
+	if !slices.Equal(seen, want) {
+		t.Errorf("listed %v, want %v", seen, want)
+	}
+}
+
+func TestOrdersInvalidArguments(t *testing.T) {
+	h := newTestHandler(t, newFakeQueryUsecase(1))
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ orders(first: 0) { pageInfo { hasNextPage } } }`, errInvalidFirst.Error()},
+		{`{ orders(first: 101) { pageInfo { hasNextPage } } }`, errInvalidFirst.Error()},
+		{`{ orders(after: "not a cursor") { pageInfo { hasNextPage } } }`, errInvalidCursor.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			response := execute(t, h, nil, tt.query, nil)
+			if len(response.Errors) != 1 || response.Errors[0].Message != tt.want {
+				t.Errorf("errors %+v, want %q", response.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerRejectsInvalidBody(t *testing.T) {
+	h := newTestHandler(t, newFakeQueryUsecase(1))
+
+	tests := []struct {
+		name, body string
+		status     int
+	}{
+		{"not json", "query", http.StatusBadRequest},
+		{"no query", `{"variables":{}}`, http.StatusBadRequest},
+		{"too large", `{"query":"` + strings.Repeat("a", maxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := domain.OrderCursor{
+		DateCreated: time.Date(2026, 10, 19, 12, 0, 0, 123456789, time.UTC),
+		OrderUID:    "b563feb7b2b84b6test",
+	}
+
+	got, err := decodeCursor(encodeCursor(cursor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.DateCreated.Equal(cursor.DateCreated) || got.OrderUID != cursor.OrderUID {
+		t.Errorf("decoded %+v, want %+v", got, cursor)
+	}
+}
+
+func TestInt32Of(t *testing.T) {
+	tests := []struct {
+		v    int
+		want int32
+	}{
+		{42, 42},
+		{math.MaxInt32 + 1, math.MaxInt32},
+		{math.MinInt32 - 1, math.MinInt32},
+	}
+	for _, tt := range tests {
+		if got := int32Of(tt.v); got != tt.want {
+			t.Errorf("int32Of(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
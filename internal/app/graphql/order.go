@@ -0,0 +1,180 @@
+package graphql
+
+import (
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+func (r *orderResolver) OrderUID() string {
+	return r.order.OrderUID
+}
+
+func (r *orderResolver) TrackNumber() string {
+	return r.order.TrackNumber
+}
+
+func (r *orderResolver) Entry() string {
+	return r.order.Entry
+}
+
+func (r *orderResolver) Locale() string {
+	return r.order.Locale
+}
+
+func (r *orderResolver) InternalSignature() string {
+	return r.order.InternalSignature
+}
+
+func (r *orderResolver) CustomerID() string {
+	return r.order.CustomerID
+}
+
+func (r *orderResolver) DeliveryService() string {
+	return r.order.DeliveryService
+}
+
+func (r *orderResolver) Shardkey() string {
+	return r.order.ShardKey
+}
+
+func (r *orderResolver) SmID() int32 {
+	return int32Of(r.order.SmID)
+}
+
+func (r *orderResolver) DateCreated() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.order.DateCreated}
+}
+
+func (r *orderResolver) OofShard() string {
+	return r.order.OofShard
+}
+
+func (r *orderResolver) Delivery() *deliveryResolver {
+	return &deliveryResolver{delivery: &r.order.Delivery}
+}
+
+func (r *orderResolver) Payment() *paymentResolver {
+	return &paymentResolver{payment: &r.order.Payment}
+}
+
+func (r *orderResolver) Items() []*itemResolver {
+	items := make([]*itemResolver, 0, len(r.order.Items))
+	for i := range r.order.Items {
+		items = append(items, &itemResolver{item: &r.order.Items[i]})
+	}
+	return items
+}
+
+func (r *deliveryResolver) Name() string {
+	return r.delivery.Name
+}
+
+func (r *deliveryResolver) Phone() string {
+	return r.delivery.Phone
+}
+
+func (r *deliveryResolver) Zip() string {
+	return r.delivery.Zip
+}
+
+func (r *deliveryResolver) City() string {
+	return r.delivery.City
+}
+
+func (r *deliveryResolver) Address() string {
+	return r.delivery.Address
+}
+
+func (r *deliveryResolver) Region() string {
+	return r.delivery.Region
+}
+
+func (r *deliveryResolver) Email() string {
+	return r.delivery.Email
+}
+
+func (r *paymentResolver) Transaction() string {
+	return r.payment.Transaction
+}
+
+func (r *paymentResolver) RequestID() string {
+	return r.payment.RequestID
+}
+
+func (r *paymentResolver) Currency() string {
+	return r.payment.Currency
+}
+
+func (r *paymentResolver) Provider() string {
+	return r.payment.Provider
+}
+
+func (r *paymentResolver) Amount() int32 {
+	return int32Of(r.payment.Amount)
+}
+
+// PaymentDt is stored as Unix seconds.
+func (r *paymentResolver) PaymentDt() graphqlgo.Time {
+	return graphqlgo.Time{Time: time.Unix(r.payment.PaymentDT, 0).UTC()}
+}
+
+func (r *paymentResolver) Bank() string {
+	return r.payment.Bank
+}
+
+func (r *paymentResolver) DeliveryCost() int32 {
+	return int32Of(r.payment.DeliveryCost)
+}
+
+func (r *paymentResolver) GoodsTotal() int32 {
+	return int32Of(r.payment.GoodsTotal)
+}
+
+func (r *paymentResolver) CustomFee() int32 {
+	return int32Of(r.payment.CustomFee)
+}
+
+func (r *itemResolver) ChrtID() int32 {
+	return int32Of(r.item.ChrtID)
+}
+
+func (r *itemResolver) TrackNumber() string {
+	return r.item.TrackNumber
+}
+
+func (r *itemResolver) Price() int32 {
+	return int32Of(r.item.Price)
+}
+
+func (r *itemResolver) RID() string {
+	return r.item.RID
+}
+
+func (r *itemResolver) Name() string {
+	return r.item.Name
+}
+
+func (r *itemResolver) Sale() int32 {
+	return int32Of(r.item.Sale)
+}
+
+func (r *itemResolver) Size() string {
+	return r.item.Size
+}
+
+func (r *itemResolver) TotalPrice() int32 {
+	return int32Of(r.item.TotalPrice)
+}
+
+func (r *itemResolver) NmID() int32 {
+	return int32Of(r.item.NmID)
+}
+
+func (r *itemResolver) Brand() string {
+	return r.item.Brand
+}
+
+func (r *itemResolver) Status() int32 {
+	return int32Of(r.item.Status)
+}
@@ -0,0 +1,244 @@
+// Package graphql loads only the order tables a query selects.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+const (
+	maxPageSize     = 100
+	cursorSeparator = "|"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+type (
+	queryUsecase interface {
+		GetOrder(ctx context.Context, orderUID string, parts domain.OrderParts) (*domain.Order, error)
+		ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+			limit int, parts domain.OrderParts) ([]*domain.Order, error)
+	}
+	logger interface {
+		Info(msg string, fields ...zap.Field)
+		Error(msg string, fields ...zap.Field)
+	}
+
+	resolver struct {
+		usecase queryUsecase
+		logger  logger
+	}
+
+	orderArgs struct {
+		UID string
+	}
+	ordersArgs struct {
+		Filter *orderFilterInput
+		First  int32
+		After  *string
+	}
+	orderFilterInput struct {
+		CustomerID      *string
+		DeliveryService *string
+		DateFrom        *graphqlgo.Time
+		DateTo          *graphqlgo.Time
+	}
+
+	orderConnection struct {
+		edges    []*orderEdge
+		pageInfo *pageInfo
+	}
+	orderEdge struct {
+		cursor string
+		node   *orderResolver
+	}
+	pageInfo struct {
+		hasNextPage bool
+		endCursor   *string
+	}
+
+	orderResolver struct {
+		order *domain.Order
+	}
+	deliveryResolver struct {
+		delivery *domain.Delivery
+	}
+	paymentResolver struct {
+		payment *domain.Payment
+	}
+	itemResolver struct {
+		item *domain.Item
+	}
+)
+
+var (
+	errInternal      = errors.New("internal error")
+	errInvalidUID    = errors.New("invalid order uid")
+	errInvalidCursor = errors.New("invalid cursor")
+	errInvalidFirst  = fmt.Errorf("first must be between 1 and %d", maxPageSize)
+)
+
+func (r *resolver) Order(ctx context.Context, args orderArgs) (*orderResolver, error) {
+	if !domain.ValidOrderUID(args.UID) {
+		return nil, errInvalidUID
+	}
+
+	order, err := r.usecase.GetOrder(ctx, args.UID, selectedParts(ctx, ""))
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		return nil, nil
+	case err != nil:
+		loggerFrom(ctx, r.logger).Error("queryUsecase.GetOrder", zap.Error(err))
+		return nil, errInternal
+	}
+
+	return &orderResolver{order: redact.Visible(ctx, order)}, nil
+}
+
+func (r *resolver) Orders(ctx context.Context, args ordersArgs) (*orderConnection, error) {
+	limit := int(args.First)
+	if limit < 1 || limit > maxPageSize {
+		return nil, errInvalidFirst
+	}
+
+	var after *domain.OrderCursor
+	if args.After != nil {
+		cursor, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+	}
+
+	orders, err := r.usecase.ListOrders(ctx, args.Filter.orderFilter(), after, limit+1,
+		selectedParts(ctx, "edges.node."))
+	if err != nil {
+		loggerFrom(ctx, r.logger).Error("queryUsecase.ListOrders", zap.Error(err))
+		return nil, errInternal
+	}
+
+	conn := &orderConnection{
+		edges:    make([]*orderEdge, 0, min(len(orders), limit)),
+		pageInfo: &pageInfo{hasNextPage: len(orders) > limit},
+	}
+	for _, order := range orders[:min(len(orders), limit)] {
+		conn.edges = append(conn.edges, &orderEdge{
+			cursor: encodeCursor(domain.CursorOf(order)),
+			node:   &orderResolver{order: redact.Visible(ctx, order)},
+		})
+	}
+	if len(conn.edges) > 0 {
+		conn.pageInfo.endCursor = &conn.edges[len(conn.edges)-1].cursor
+	}
+
+	return conn, nil
+}
+
+func loggerFrom(ctx context.Context, fallback logger) logger {
+	if l, ok := logctx.Logger(ctx); ok {
+		return l
+	}
+	return fallback
+}
+
+// selectedParts takes the path of the order field relative to the resolver.
+func selectedParts(ctx context.Context, path string) domain.OrderParts {
+	return domain.OrderParts{
+		Delivery: graphqlgo.HasSelectedField(ctx, path+"delivery"),
+		Payment:  graphqlgo.HasSelectedField(ctx, path+"payment"),
+		Items:    graphqlgo.HasSelectedField(ctx, path+"items"),
+	}
+}
+
+func (f *orderFilterInput) orderFilter() domain.OrderFilter {
+	filter := domain.OrderFilter{}
+	if f == nil {
+		return filter
+	}
+	if f.CustomerID != nil {
+		filter.CustomerID = *f.CustomerID
+	}
+	if f.DeliveryService != nil {
+		filter.DeliveryService = *f.DeliveryService
+	}
+	if f.DateFrom != nil {
+		filter.CreatedFrom = f.DateFrom.Time
+	}
+	if f.DateTo != nil {
+		filter.CreatedTo = f.DateTo.Time
+	}
+	return filter
+}
+
+func encodeCursor(cursor domain.OrderCursor) string {
+	raw := cursor.DateCreated.UTC().Format(time.RFC3339Nano) + cursorSeparator + cursor.OrderUID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (domain.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.OrderCursor{}, errInvalidCursor
+	}
+	created, orderUID, found := strings.Cut(string(raw), cursorSeparator)
+	if !found {
+		return domain.OrderCursor{}, errInvalidCursor
+	}
+	dateCreated, err := time.Parse(time.RFC3339Nano, created)
+	if err != nil {
+		return domain.OrderCursor{}, errInvalidCursor
+	}
+
+	return domain.OrderCursor{
+		DateCreated: dateCreated,
+		OrderUID:    orderUID,
+	}, nil
+}
+
+func int32Of(v int) int32 {
+	switch {
+	case v > math.MaxInt32:
+		return math.MaxInt32
+	case v < math.MinInt32:
+		return math.MinInt32
+	}
+	return int32(v)
+}
+
+func (c *orderConnection) Edges() []*orderEdge {
+	return c.edges
+}
+
+func (c *orderConnection) PageInfo() *pageInfo {
+	return c.pageInfo
+}
+
+func (e *orderEdge) Cursor() string {
+	return e.cursor
+}
+
+func (e *orderEdge) Node() *orderResolver {
+	return e.node
+}
+
+func (p *pageInfo) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfo) EndCursor() *string {
+	return p.endCursor
+}
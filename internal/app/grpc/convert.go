@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	orderv1 "github.com/AndrejDubinin/wbtech-l0/pkg/api/order/v1"
+)
+
+func toProtoOrder(order *domain.Order) *orderv1.Order {
+	items := make([]*orderv1.Item, 0, len(order.Items))
+	for _, it := range order.Items {
+		items = append(items, &orderv1.Item{
+			ChrtId:      int64(it.ChrtID),
+			TrackNumber: it.TrackNumber,
+			Price:       int64(it.Price),
+			Rid:         it.RID,
+			Name:        it.Name,
+			Sale:        int64(it.Sale),
+			Size:        it.Size,
+			TotalPrice:  int64(it.TotalPrice),
+			NmId:        int64(it.NmID),
+			Brand:       it.Brand,
+			Status:      int64(it.Status),
+		})
+	}
+
+	return &orderv1.Order{
+		OrderUid:    order.OrderUID,
+		TrackNumber: order.TrackNumber,
+		Entry:       order.Entry,
+		Delivery: &orderv1.Delivery{
+			Name:    order.Delivery.Name,
+			Phone:   order.Delivery.Phone,
+			Zip:     order.Delivery.Zip,
+			City:    order.Delivery.City,
+			Address: order.Delivery.Address,
+			Region:  order.Delivery.Region,
+			Email:   order.Delivery.Email,
+		},
+		Payment: &orderv1.Payment{
+			Transaction:  order.Payment.Transaction,
+			RequestId:    order.Payment.RequestID,
+			Currency:     order.Payment.Currency,
+			Provider:     order.Payment.Provider,
+			Amount:       int64(order.Payment.Amount),
+			PaymentDt:    order.Payment.PaymentDT,
+			Bank:         order.Payment.Bank,
+			DeliveryCost: int64(order.Payment.DeliveryCost),
+			GoodsTotal:   int64(order.Payment.GoodsTotal),
+			CustomFee:    int64(order.Payment.CustomFee),
+		},
+		Items:             items,
+		Locale:            order.Locale,
+		InternalSignature: order.InternalSignature,
+		CustomerId:        order.CustomerID,
+		DeliveryService:   order.DeliveryService,
+		Shardkey:          order.ShardKey,
+		SmId:              int64(order.SmID),
+		DateCreated:       timestamppb.New(order.DateCreated),
+		OofShard:          order.OofShard,
+	}
+}
+
+func toOrderFilter(req *orderv1.ListOrdersRequest) domain.OrderFilter {
+	filter := domain.OrderFilter{
+		CustomerID:      req.GetCustomerId(),
+		DeliveryService: req.GetDeliveryService(),
+	}
+	if req.GetCreatedFrom() != nil {
+		filter.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		filter.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+	return filter
+}
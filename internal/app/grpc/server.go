@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+	orderv1 "github.com/AndrejDubinin/wbtech-l0/pkg/api/order/v1"
+)
+
+type (
+	getOrderUsecase interface {
+		GetOrder(ctx context.Context, orderUID string) (*domain.Order, error)
+	}
+	batchGetOrdersUsecase interface {
+		GetOrders(ctx context.Context, orderUIDs []string) ([]*domain.Order, []string, error)
+	}
+	listOrdersUsecase interface {
+		ExportOrders(ctx context.Context, filter domain.OrderFilter, w domain.OrderWriter) (int, error)
+	}
+	logger interface {
+		Info(msg string, fields ...zap.Field)
+		Error(msg string, fields ...zap.Field)
+	}
+
+	OrderServer struct {
+		orderv1.UnimplementedOrderServiceServer
+		getUsecase      getOrderUsecase
+		batchGetUsecase batchGetOrdersUsecase
+		listUsecase     listOrdersUsecase
+		batchGetLimit   int
+		logger          logger
+	}
+
+	streamWriter struct {
+		ctx    context.Context
+		stream orderv1.OrderService_ListOrdersServer
+	}
+)
+
+func NewOrderServer(get getOrderUsecase, batchGet batchGetOrdersUsecase, list listOrdersUsecase,
+	batchGetLimit int, logger logger,
+) *OrderServer {
+	return &OrderServer{
+		getUsecase:      get,
+		batchGetUsecase: batchGet,
+		listUsecase:     list,
+		batchGetLimit:   batchGetLimit,
+		logger:          logger,
+	}
+}
+
+func (s *OrderServer) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
+	if !domain.ValidOrderUID(req.GetOrderUid()) {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_uid")
+	}
+
+	order, err := s.getUsecase.GetOrder(ctx, req.GetOrderUid())
+	if err != nil {
+		return nil, s.statusError(ctx, "getOrderUsecase.GetOrder", err)
+	}
+
+	return &orderv1.GetOrderResponse{
+		Order: toProtoOrder(redact.Visible(ctx, order)),
+	}, nil
+}
+
+func (s *OrderServer) BatchGetOrders(ctx context.Context, req *orderv1.BatchGetOrdersRequest,
+) (*orderv1.BatchGetOrdersResponse, error) {
+	uids := req.GetOrderUids()
+	switch {
+	case len(uids) == 0:
+		return nil, status.Error(codes.InvalidArgument, "order_uids is empty")
+	case len(uids) > s.batchGetLimit:
+		return nil, status.Errorf(codes.InvalidArgument, "too many order_uids, max %d", s.batchGetLimit)
+	}
+	for i, uid := range uids {
+		if !domain.ValidOrderUID(uid) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid order_uids[%d]", i)
+		}
+	}
+
+	orders, missing, err := s.batchGetUsecase.GetOrders(ctx, uids)
+	if err != nil {
+		return nil, s.statusError(ctx, "batchGetOrdersUsecase.GetOrders", err)
+	}
+
+	resp := &orderv1.BatchGetOrdersResponse{
+		Orders:  make([]*orderv1.Order, 0, len(orders)),
+		Missing: missing,
+	}
+	for _, order := range orders {
+		resp.Orders = append(resp.Orders, toProtoOrder(redact.Visible(ctx, order)))
+	}
+
+	return resp, nil
+}
+
+func (s *OrderServer) ListOrders(req *orderv1.ListOrdersRequest, stream orderv1.OrderService_ListOrdersServer) error {
+	ctx := stream.Context()
+	count, err := s.listUsecase.ExportOrders(ctx, toOrderFilter(req), &streamWriter{ctx: ctx, stream: stream})
+	if err != nil {
+		return s.statusError(ctx, "listOrdersUsecase.ExportOrders", fmt.Errorf("after %d orders: %w", count, err))
+	}
+	return nil
+}
+
+func (s *OrderServer) statusError(ctx context.Context, op string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		return status.Error(codes.NotFound, domain.ErrOrderNotFound.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	l := s.logger
+	if ctxLogger, ok := logctx.Logger(ctx); ok {
+		l = ctxLogger
+	}
+	l.Error(op, zap.Error(err))
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func (w *streamWriter) Write(order *domain.Order) error {
+	return w.stream.Send(&orderv1.ListOrdersResponse{
+		Order: toProtoOrder(redact.Visible(w.ctx, order)),
+	})
+}
+
+func (w *streamWriter) Flush() error {
+	return nil
+}
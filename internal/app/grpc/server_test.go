@@ -0,0 +1,273 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	grpcMw "github.com/AndrejDubinin/wbtech-l0/internal/middleware/grpc"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+	orderv1 "github.com/AndrejDubinin/wbtech-l0/pkg/api/order/v1"
+)
+
+const (
+	piiKey    = "pii-key"
+	readerKey = "reader-key"
+	writerKey = "writer-key"
+)
+
+var errUnknownKey = errors.New("unknown key")
+
+type (
+	fakeUsecase struct {
+		orders map[string]*domain.Order
+		filter domain.OrderFilter
+		err    error
+	}
+
+	fakeAuthenticator map[string]auth.Principal
+)
+
+func (u *fakeUsecase) GetOrder(_ context.Context, orderUID string) (*domain.Order, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	if order, inMap := u.orders[orderUID]; inMap {
+		return order, nil
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+func (u *fakeUsecase) GetOrders(_ context.Context, orderUIDs []string) ([]*domain.Order, []string, error) {
+	var found []*domain.Order
+	var missing []string
+	for _, uid := range orderUIDs {
+		if order, inMap := u.orders[uid]; inMap {
+			found = append(found, order)
+		} else {
+			missing = append(missing, uid)
+		}
+	}
+	return found, missing, u.err
+}
+
+func (u *fakeUsecase) ExportOrders(_ context.Context, filter domain.OrderFilter, w domain.OrderWriter) (int, error) {
+	u.filter = filter
+	uids := slices.Sorted(maps.Keys(u.orders))
+	for i, uid := range uids {
+		if err := w.Write(u.orders[uid]); err != nil {
+			return i, err
+		}
+	}
+	return len(uids), u.err
+}
+
+func (a fakeAuthenticator) AuthenticateCredentials(key, _ string) (auth.Principal, error) {
+	if principal, inMap := a[key]; inMap {
+		return principal, nil
+	}
+	return auth.Principal{}, errUnknownKey
+}
+
+func newTestClient(t *testing.T, u *fakeUsecase) orderv1.OrderServiceClient {
+	t.Helper()
+	authenticator := fakeAuthenticator{
+		piiKey:    {Subject: "pii", Method: "api_key", Scopes: []string{auth.ScopeOrdersReadPII}},
+		readerKey: {Subject: "reader", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}},
+		writerKey: {Subject: "writer", Method: "api_key", Scopes: []string{auth.ScopeOrdersWrite}},
+	}
+	scopes := map[string]string{
+		orderv1.OrderService_GetOrder_FullMethodName:       auth.ScopeOrdersRead,
+		orderv1.OrderService_BatchGetOrders_FullMethodName: auth.ScopeOrdersRead,
+		orderv1.OrderService_ListOrders_FullMethodName:     auth.ScopeOrdersRead,
+	}
+	logger := zap.NewNop()
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcMw.AuthUnaryInterceptor(authenticator, scopes, logger)),
+		grpc.ChainStreamInterceptor(grpcMw.AuthStreamInterceptor(authenticator, scopes, logger)),
+	)
+	orderv1.RegisterOrderServiceServer(server, NewOrderServer(u, u, u, 2, logger))
+
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return orderv1.NewOrderServiceClient(conn)
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func testOrders(n int) (*fakeUsecase, []domain.Order) {
+	orders := fixtures.New(1).Orders(n)
+	u := &fakeUsecase{orders: map[string]*domain.Order{}}
+	for i := range orders {
+		u.orders[orders[i].OrderUID] = &orders[i]
+	}
+	return u, orders
+}
+
+func TestGetOrder(t *testing.T) {
+	u, orders := testOrders(1)
+	order := orders[0]
+	client := newTestClient(t, u)
+
+	tests := []struct {
+		name, key string
+		pii       bool
+	}{
+		{"with PII", piiKey, true},
+		{"without PII", readerKey, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetOrder(withKey(tt.key), &orderv1.GetOrderRequest{OrderUid: order.OrderUID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := resp.GetOrder()
+			if got.GetOrderUid() != order.OrderUID || len(got.GetItems()) != len(order.Items) ||
+				!got.GetDateCreated().AsTime().Equal(order.DateCreated) {
+				t.Errorf("order %v", got)
+			}
+			if masked := got.GetDelivery().GetName() != order.Delivery.Name; masked == tt.pii {
+				t.Errorf("name %q with PII %v", got.GetDelivery().GetName(), tt.pii)
+			}
+			if masked := got.GetDelivery().GetPhone() != order.Delivery.Phone; masked == tt.pii {
+				t.Errorf("phone %q with PII %v", got.GetDelivery().GetPhone(), tt.pii)
+			}
+			if got.GetDelivery().GetCity() != order.Delivery.City {
+				t.Errorf("city %q", got.GetDelivery().GetCity())
+			}
+		})
+	}
+}
+
+func TestGetOrderErrors(t *testing.T) {
+	u, orders := testOrders(1)
+	client := newTestClient(t, u)
+
+	tests := []struct {
+		name, key, uid string
+		err            error
+		code           codes.Code
+	}{
+		{"no credentials", "", orders[0].OrderUID, nil, codes.Unauthenticated},
+		{"unknown key", "stolen", orders[0].OrderUID, nil, codes.Unauthenticated},
+		{"missing scope", writerKey, orders[0].OrderUID, nil, codes.PermissionDenied},
+		{"invalid uid", readerKey, "bad uid!", nil, codes.InvalidArgument},
+		{"not found", readerKey, "b563feb7b2b84b6missing", nil, codes.NotFound},
+		{"usecase", readerKey, orders[0].OrderUID, errors.New("db down"), codes.Internal},
+		{"deadline", readerKey, orders[0].OrderUID, context.DeadlineExceeded, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u.err = tt.err
+			ctx := context.Background()
+			if tt.key != "" {
+				ctx = withKey(tt.key)
+			}
+			_, err := client.GetOrder(ctx, &orderv1.GetOrderRequest{OrderUid: tt.uid})
+			if status.Code(err) != tt.code {
+				t.Errorf("err %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestBatchGetOrders(t *testing.T) {
+	u, orders := testOrders(2)
+	client := newTestClient(t, u)
+
+	resp, err := client.BatchGetOrders(withKey(readerKey), &orderv1.BatchGetOrdersRequest{
+		OrderUids: []string{orders[0].OrderUID, "b563feb7b2b84b6missing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetOrders()) != 1 || resp.GetOrders()[0].GetOrderUid() != orders[0].OrderUID ||
+		!slices.Equal(resp.GetMissing(), []string{"b563feb7b2b84b6missing"}) {
+		t.Fatalf("response %v", resp)
+	}
+	if resp.GetOrders()[0].GetDelivery().GetEmail() == orders[0].Delivery.Email {
+		t.Error("email not masked without PII")
+	}
+
+	for _, uids := range [][]string{nil, {orders[0].OrderUID, orders[1].OrderUID, orders[0].OrderUID}, {"bad uid!"}} {
+		_, err := client.BatchGetOrders(withKey(readerKey), &orderv1.BatchGetOrdersRequest{OrderUids: uids})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("uids %v: err %v, want InvalidArgument", uids, err)
+		}
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	u, orders := testOrders(3)
+	client := newTestClient(t, u)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	stream, err := client.ListOrders(withKey(piiKey), &orderv1.ListOrdersRequest{
+		CustomerId: "c-1", DeliveryService: "meest", CreatedFrom: timestamppb.New(from),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, resp.GetOrder().GetDelivery().GetName())
+	}
+	if len(names) != len(orders) {
+		t.Fatalf("received %d orders, want %d", len(names), len(orders))
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(orders, func(order domain.Order) bool { return order.Delivery.Name == name }) {
+			t.Errorf("name %q masked with PII", name)
+		}
+	}
+	want := domain.OrderFilter{CustomerID: "c-1", DeliveryService: "meest", CreatedFrom: from}
+	if u.filter != want {
+		t.Errorf("filter %+v, want %+v", u.filter, want)
+	}
+
+	stream, err = client.ListOrders(withKey(writerKey), &orderv1.ListOrdersRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("err %v, want PermissionDenied", err)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	appGrpc "github.com/AndrejDubinin/wbtech-l0/internal/app/grpc"
+	grpcMw "github.com/AndrejDubinin/wbtech-l0/internal/middleware/grpc"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/batchget"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/get"
+	orderv1 "github.com/AndrejDubinin/wbtech-l0/pkg/api/order/v1"
+)
+
+// grpcScopes leaves health and reflection open.
+var grpcScopes = map[string]string{
+	orderv1.OrderService_GetOrder_FullMethodName:       auth.ScopeOrdersRead,
+	orderv1.OrderService_BatchGetOrders_FullMethodName: auth.ScopeOrdersRead,
+	orderv1.OrderService_ListOrders_FullMethodName:     auth.ScopeOrdersRead,
+}
+
+type grpcServer struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+func (a *App) newGRPCServer(logger *zap.Logger) *grpcServer {
+	unary := []grpc.UnaryServerInterceptor{
+		grpcMw.RequestIDUnaryInterceptor(logger),
+		grpcMw.PanicUnaryInterceptor(logger),
+		grpcMw.AccessLogUnaryInterceptor(logger),
+		grpcMw.AuthUnaryInterceptor(a.auth, grpcScopes, logger),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		grpcMw.RequestIDStreamInterceptor(logger),
+		grpcMw.PanicStreamInterceptor(logger),
+		grpcMw.AccessLogStreamInterceptor(logger),
+		grpcMw.AuthStreamInterceptor(a.auth, grpcScopes, logger),
+	}
+	if a.limiter != nil {
+		unary = append(unary, grpcMw.RateLimitUnaryInterceptor(a.limiter, logger))
+		stream = append(stream, grpcMw.RateLimitStreamInterceptor(a.limiter, logger))
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+
+	orderv1.RegisterOrderServiceServer(server, appGrpc.NewOrderServer(
+		get.New(a.storage, a.cache), batchget.New(a.storage, a.cache), export.New(a.storage),
+		a.config.batchGetLimit, logger))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(orderv1.OrderService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &grpcServer{
+		server: server,
+		health: healthServer,
+	}
+}
+
+func (s *grpcServer) serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("net.Listen: %w", err)
+	}
+	return s.server.Serve(lis)
+}
+
+func (s *grpcServer) shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
+	httpMw "github.com/AndrejDubinin/wbtech-l0/internal/middleware/http"
+)
+
+func TestHandlerLogsRoute(t *testing.T) {
+	config := NewConfig(Options{
+		RateLimit: 50, RateBurst: 100, RateClients: 100, MaxInFlight: 10, CompressMinSize: 1024,
+		AccessLogSample: 1,
+	})
+	authenticator := httpMw.NewDisabledAuthenticator()
+	ips, err := httpMw.NewClientIPResolver(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := ratelimit.New(config.limits.rateLimit, config.limits.rateBurst, config.limits.rateClients)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+	mux := http.NewServeMux()
+	mux.Handle("GET /order/{order_uid}", httpMw.AuthMiddleware(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) },
+	), authenticator, auth.ScopeOrdersRead, logger))
+	h := newHandler(mux, config, limiter, authenticator, ips, logger)
+
+	tests := []struct {
+		target, route string
+	}{
+		{"/order/b563feb7b2b84b6test", "GET /order/{order_uid}"},
+		{"/missing", "unmatched"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			entries := logs.TakeAll()
+			if len(entries) != 1 {
+				t.Fatalf("%d log entries", len(entries))
+			}
+			if got := entries[0].ContextMap()["route"]; got != tt.route {
+				t.Errorf("route %v, want %q", got, tt.route)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	paramCustomerOrdersLimit  = "limit"
+	paramCustomerOrdersOffset = "offset"
+
+	defaultCustomerOrdersLimit = 20
+	maxCustomerOrdersLimit     = 100
+)
+
+type (
+	customerOrdersUsecase interface {
+		GetSummary(ctx context.Context, customerID string) (*domain.CustomerSummary, error)
+		GetOrders(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error)
+	}
+
+	CustomerOrdersResponse struct {
+		CustomerID string          `json:"customer_id"`
+		Summary    CustomerSummary `json:"summary"`
+		Orders     []*domain.Order `json:"orders"`
+		Limit      int             `json:"limit"`
+		Offset     int             `json:"offset"`
+	}
+	CustomerSummary struct {
+		Orders       int              `json:"orders"`
+		Spent        []CurrencyAmount `json:"spent"`
+		FirstOrderAt time.Time        `json:"first_order_at"`
+		LastOrderAt  time.Time        `json:"last_order_at"`
+		TopCity      string           `json:"top_city"`
+	}
+	CurrencyAmount struct {
+		Currency string `json:"currency"`
+		Amount   int64  `json:"amount"`
+	}
+
+	CustomerOrdersHandler struct {
+		name    string
+		usecase customerOrdersUsecase
+		logger  logger
+	}
+)
+
+func NewCustomerOrdersHandler(usecase customerOrdersUsecase, name string, logger logger) *CustomerOrdersHandler {
+	return &CustomerOrdersHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *CustomerOrdersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue(definitions.ParamCustomerID)
+	if !domain.ValidCustomerID(customerID) {
+		WriteProblem(w, r, ErrInvalidParameter, fmt.Sprintf("invalid %s", definitions.ParamCustomerID))
+		return
+	}
+	query := r.URL.Query()
+	limit, err := intParam(query, paramCustomerOrdersLimit, defaultCustomerOrdersLimit, 1, maxCustomerOrdersLimit)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+	offset, err := intParam(query, paramCustomerOrdersOffset, 0, 0, math.MaxInt32)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	logger := loggerFrom(ctx, h.logger)
+	summary, err := h.usecase.GetSummary(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCustomerNotFound) {
+			WriteProblem(w, r, err, "")
+			return
+		}
+		logger.Error("customerUsecase.GetSummary", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	orders, err := h.usecase.GetOrders(ctx, customerID, limit, offset)
+	if err != nil {
+		logger.Error("customerUsecase.GetOrders", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	visible := make([]*domain.Order, 0, len(orders))
+	for _, order := range orders {
+		visible = append(visible, redact.Visible(ctx, order))
+	}
+	response, err := json.Marshal(CustomerOrdersResponse{
+		CustomerID: customerID,
+		Summary:    customerSummary(summary),
+		Orders:     visible,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		logger.Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	GetSuccessResponseWithBody(w, response)
+}
+
+func customerSummary(summary *domain.CustomerSummary) CustomerSummary {
+	spent := make([]CurrencyAmount, 0, len(summary.Spent))
+	for _, s := range summary.Spent {
+		spent = append(spent, CurrencyAmount{Currency: s.Currency, Amount: s.Amount})
+	}
+	return CustomerSummary{
+		Orders:       summary.Orders,
+		Spent:        spent,
+		FirstOrderAt: summary.FirstOrderAt,
+		LastOrderAt:  summary.LastOrderAt,
+		TopCity:      summary.TopCity,
+	}
+}
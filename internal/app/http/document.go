@@ -0,0 +1,32 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
+
+// DocumentHandler serves a fixed in-memory document, such as the OpenAPI
+// specification, with a strong ETag so clients revalidate cheaply.
+type DocumentHandler struct {
+	contentType string
+	data        []byte
+	etag        string
+	modTime     time.Time
+}
+
+func NewDocumentHandler(contentType string, data []byte) *DocumentHandler {
+	return &DocumentHandler{
+		contentType: contentType,
+		data:        data,
+		etag:        strongETag(data),
+		modTime:     time.Now(),
+	}
+}
+
+func (h *DocumentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", h.contentType)
+	w.Header().Set("Cache-Control", staticIndexCache)
+	w.Header().Set("ETag", h.etag)
+	http.ServeContent(w, r, "", h.modTime, bytes.NewReader(h.data))
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	paramErasureReason = "reason"
+	maxErasureReason   = 500
+)
+
+type (
+	erasureUsecase interface {
+		AnonymizeCustomer(ctx context.Context, customerID string, request domain.ErasureRequest,
+		) (*domain.Erasure, error)
+		DeleteOrder(ctx context.Context, orderUID string, request domain.ErasureRequest) (*domain.Erasure, error)
+	}
+
+	ErasureResponse struct {
+		AuditID   int64     `json:"audit_id"`
+		Action    string    `json:"action"`
+		Orders    int       `json:"orders"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	AnonymizeCustomerHandler struct {
+		name    string
+		usecase erasureUsecase
+		logger  logger
+	}
+	DeleteOrderHandler struct {
+		name    string
+		usecase erasureUsecase
+		logger  logger
+	}
+)
+
+func NewAnonymizeCustomerHandler(usecase erasureUsecase, name string, logger logger) *AnonymizeCustomerHandler {
+	return &AnonymizeCustomerHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func NewDeleteOrderHandler(usecase erasureUsecase, name string, logger logger) *DeleteOrderHandler {
+	return &DeleteOrderHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *AnonymizeCustomerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue(definitions.ParamCustomerID)
+	if !domain.ValidCustomerID(customerID) {
+		WriteProblem(w, r, ErrInvalidParameter, fmt.Sprintf("invalid %s", definitions.ParamCustomerID))
+		return
+	}
+	request, err := erasureRequest(r)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+
+	erasure, err := h.usecase.AnonymizeCustomer(r.Context(), customerID, request)
+	writeErasure(w, r, erasure, err, "erasureUsecase.AnonymizeCustomer", h.logger)
+}
+
+func (h *DeleteOrderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	orderUID := r.PathValue(definitions.ParamOrderUID)
+	if !domain.ValidOrderUID(orderUID) {
+		WriteProblem(w, r, ErrInvalidParameter, fmt.Sprintf("invalid %s", definitions.ParamOrderUID))
+		return
+	}
+	request, err := erasureRequest(r)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+
+	erasure, err := h.usecase.DeleteOrder(r.Context(), orderUID, request)
+	writeErasure(w, r, erasure, err, "erasureUsecase.DeleteOrder", h.logger)
+}
+
+func erasureRequest(r *http.Request) (domain.ErasureRequest, error) {
+	reason := r.URL.Query().Get(paramErasureReason)
+	if utf8.RuneCountInString(reason) > maxErasureReason {
+		return domain.ErasureRequest{}, fmt.Errorf("%s must be at most %d characters", paramErasureReason,
+			maxErasureReason)
+	}
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		principal = auth.Anonymous
+	}
+	return domain.ErasureRequest{
+		RequestedBy: principal.ID(),
+		Reason:      reason,
+	}, nil
+}
+
+func writeErasure(w http.ResponseWriter, r *http.Request, erasure *domain.Erasure, err error, op string,
+	fallback logger,
+) {
+	logger := loggerFrom(r.Context(), fallback)
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound), errors.Is(err, domain.ErrCustomerNotFound):
+		WriteProblem(w, r, err, "")
+		return
+	case err != nil:
+		logger.Error(op, zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	logger.Info("erasure completed", zap.Int64("auditID", erasure.ID), zap.String("action", string(erasure.Action)),
+		zap.Int("orders", len(erasure.OrderUIDs)))
+	response, err := json.Marshal(ErasureResponse{
+		AuditID:   erasure.ID,
+		Action:    string(erasure.Action),
+		Orders:    len(erasure.OrderUIDs),
+		CreatedAt: erasure.CreatedAt,
+	})
+	if err != nil {
+		logger.Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	GetSuccessResponseWithBody(w, response)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type fakeErasureUsecase struct {
+	subject string
+	request domain.ErasureRequest
+	err     error
+}
+
+func (u *fakeErasureUsecase) erasure(subject string, request domain.ErasureRequest, action domain.ErasureAction,
+) (*domain.Erasure, error) {
+	u.subject, u.request = subject, request
+	if u.err != nil {
+		return nil, u.err
+	}
+	return &domain.Erasure{ID: 7, Action: action, OrderUIDs: []string{"a", "b"},
+		CreatedAt: time.Date(2026, 10, 19, 17, 0, 0, 0, time.UTC)}, nil
+}
+
+func (u *fakeErasureUsecase) AnonymizeCustomer(_ context.Context, customerID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	return u.erasure(customerID, request, domain.ErasureAnonymize)
+}
+
+func (u *fakeErasureUsecase) DeleteOrder(_ context.Context, orderUID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	return u.erasure(orderUID, request, domain.ErasureDelete)
+}
+
+var eraser = auth.Principal{Subject: "support", Method: "api_key", Scopes: []string{auth.ScopeOrdersErase}}
+
+func anonymize(u erasureUsecase, customerID, rawQuery string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/admin/customers/"+url.PathEscape(customerID)+"/anonymize?"+rawQuery, nil)
+	r.SetPathValue(definitions.ParamCustomerID, customerID)
+	r = r.WithContext(auth.WithPrincipal(r.Context(), eraser))
+	rec := httptest.NewRecorder()
+	NewAnonymizeCustomerHandler(u, "anonymizeCustomer", zap.NewNop()).ServeHTTP(rec, r)
+	return rec
+}
+
+func deleteOrder(u erasureUsecase, orderUID, rawQuery string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodDelete, "/admin/orders/"+url.PathEscape(orderUID)+"?"+rawQuery, nil)
+	r.SetPathValue(definitions.ParamOrderUID, orderUID)
+	r = r.WithContext(auth.WithPrincipal(r.Context(), eraser))
+	rec := httptest.NewRecorder()
+	NewDeleteOrderHandler(u, "deleteOrder", zap.NewNop()).ServeHTTP(rec, r)
+	return rec
+}
+
+func TestErasureHandlers(t *testing.T) {
+	tests := []struct {
+		name   string
+		serve  func(u erasureUsecase) *httptest.ResponseRecorder
+		action string
+	}{
+		{"anonymize", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return anonymize(u, "c-1", "reason=ticket+1234")
+		}, "anonymize"},
+		{"delete", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return deleteOrder(u, "b563feb7b2b84b6test", "reason=ticket+1234")
+		}, "delete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &fakeErasureUsecase{}
+			rec := tt.serve(u)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			want := domain.ErasureRequest{RequestedBy: "api_key:support", Reason: "ticket 1234"}
+			if u.request != want {
+				t.Errorf("request %+v, want %+v", u.request, want)
+			}
+
+			var response ErasureResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.AuditID != 7 || response.Action != tt.action || response.Orders != 2 {
+				t.Errorf("response %+v", response)
+			}
+		})
+	}
+}
+
+func TestErasureHandlersErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		serve  func(u erasureUsecase) *httptest.ResponseRecorder
+		err    error
+		status int
+		code   string
+	}{
+		{"invalid customer", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return anonymize(u, strings.Repeat("c", 256), "")
+		}, nil, http.StatusBadRequest, "invalid_parameter"},
+		{"customer not found", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return anonymize(u, "c-1", "")
+		}, domain.ErrCustomerNotFound, http.StatusNotFound, "customer_not_found"},
+		{"invalid order", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return deleteOrder(u, "bad uid!", "")
+		}, nil, http.StatusBadRequest, "invalid_parameter"},
+		{"order not found", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return deleteOrder(u, "b563feb7b2b84b6test", "")
+		}, domain.ErrOrderNotFound, http.StatusNotFound, "order_not_found"},
+		{"reason too long", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return deleteOrder(u, "b563feb7b2b84b6test", "reason="+strings.Repeat("r", maxErasureReason+1))
+		}, nil, http.StatusBadRequest, "invalid_parameter"},
+		{"usecase", func(u erasureUsecase) *httptest.ResponseRecorder {
+			return deleteOrder(u, "b563feb7b2b84b6test", "")
+		}, errors.New("db down"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := tt.serve(&fakeErasureUsecase{err: tt.err})
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := problemCode(t, rec); got != tt.code {
+				t.Errorf("code %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
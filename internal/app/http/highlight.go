@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+// span is a half-open range of rune indexes.
+type span struct {
+	start, end int
+}
+
+// highlights keys the matched fields by path, e.g. "items[1].brand"; masked
+// fields are left out.
+func highlights(order, visible *domain.Order, search domain.OrderSearch) map[string]string {
+	terms := search.Terms()
+	found := make(map[string]string)
+	mark := func(path, value, shown string, spans []span) {
+		if len(spans) > 0 && value == shown {
+			found[path] = markSpans(value, spans)
+		}
+	}
+
+	if token := search.Token(); token != "" {
+		mark("track_number", order.TrackNumber, visible.TrackNumber,
+			partialSpans(order.TrackNumber, strings.ToLower(token)))
+	}
+
+	d, shown := order.Delivery, visible.Delivery
+	if search.PII {
+		mark("delivery.name", d.Name, shown.Name, termSpans(d.Name, terms))
+		mark("delivery.email", d.Email, shown.Email, termSpans(d.Email, terms))
+		mark("delivery.phone", d.Phone, shown.Phone,
+			append(termSpans(d.Phone, terms), digitSpans(d.Phone, search.PhoneDigits())...))
+		mark("delivery.address", d.Address, shown.Address, termSpans(d.Address, terms))
+	}
+	mark("delivery.city", d.City, shown.City, termSpans(d.City, terms))
+
+	for i, item := range order.Items {
+		mark(fmt.Sprintf("items[%d].name", i), item.Name, item.Name, termSpans(item.Name, terms))
+		mark(fmt.Sprintf("items[%d].brand", i), item.Brand, item.Brand, termSpans(item.Brand, terms))
+	}
+
+	return found
+}
+
+// termSpans matches whole words ignoring case, like the full-text search.
+func termSpans(value string, terms []string) []span {
+	runes := []rune(value)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+
+	var spans []span
+	for _, term := range terms {
+		for _, s := range indexAll(runes, []rune(term)) {
+			if !wordRuneAt(runes, s.start-1) && !wordRuneAt(runes, s.end) {
+				spans = append(spans, s)
+			}
+		}
+	}
+	return spans
+}
+
+func partialSpans(value, text string) []span {
+	runes := []rune(value)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return indexAll(runes, []rune(text))
+}
+
+func wordRuneAt(runes []rune, i int) bool {
+	return i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+}
+
+// digitSpans ignores everything but the digits of value.
+func digitSpans(value, digits string) []span {
+	if digits == "" {
+		return nil
+	}
+
+	var only []rune
+	var at []int
+	for i, r := range []rune(value) {
+		if unicode.IsDigit(r) {
+			only = append(only, r)
+			at = append(at, i)
+		}
+	}
+
+	spans := indexAll(only, []rune(digits))
+	for i, s := range spans {
+		spans[i] = span{start: at[s.start], end: at[s.end-1] + 1}
+	}
+	return spans
+}
+
+func indexAll(s, sub []rune) []span {
+	var spans []span
+	if len(sub) == 0 {
+		return spans
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			spans = append(spans, span{start: i, end: i + len(sub)})
+		}
+	}
+	return spans
+}
+
+// markSpans merges overlapping spans.
+func markSpans(value string, spans []span) string {
+	slices.SortFunc(spans, func(a, b span) int {
+		return a.start - b.start
+	})
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			last.end = max(last.end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	runes := []rune(value)
+	b := strings.Builder{}
+	done := 0
+	for _, s := range merged {
+		b.WriteString(html.EscapeString(string(runes[done:s.start])))
+		b.WriteString(highlightStart)
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString(highlightEnd)
+		done = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[done:])))
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func TestTermSpans(t *testing.T) {
+	tests := []struct {
+		value string
+		terms []string
+		want  []span
+	}{
+		{"Ivan Petrov", []string{"petrov"}, []span{{5, 11}}},
+		{"Иван Петров", []string{"петров"}, []span{{5, 11}}},
+		{"Petrovsky Petrov", []string{"petrov"}, []span{{10, 16}}},
+		{"st. Lenina 1", []string{"1", "lenina"}, []span{{11, 12}, {4, 10}}},
+		{"Moscow", []string{"mos"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := termSpans(tt.value, tt.terms); !slices.Equal(got, tt.want) {
+				t.Errorf("termSpans %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigitSpans(t *testing.T) {
+	tests := []struct {
+		value, digits string
+		want          []span
+	}{
+		{"+7 (916) 123-45-67", "916123", []span{{4, 12}}},
+		{"+79161234567", "4567", []span{{8, 12}}},
+		{"+79161234567", "", nil},
+		{"+79161234567", "000", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			if got := digitSpans(tt.value, tt.digits); !slices.Equal(got, tt.want) {
+				t.Errorf("digitSpans %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkSpans(t *testing.T) {
+	tests := []struct {
+		name, value string
+		spans       []span
+		want        string
+	}{
+		{"single", "Ivan Petrov", []span{{5, 11}}, "Ivan <mark>Petrov</mark>"},
+		{"unsorted", "a b c", []span{{4, 5}, {0, 1}}, "<mark>a</mark> b <mark>c</mark>"},
+		{"overlapping", "abcdef", []span{{0, 3}, {2, 5}}, "<mark>abcde</mark>f"},
+		{"escaped", "<b>&x</b>", []span{{3, 5}}, "&lt;b&gt;<mark>&amp;x</mark>&lt;/b&gt;"},
+		{"unicode", "Пётр Иванов", []span{{5, 11}}, "Пётр <mark>Иванов</mark>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markSpans(tt.value, tt.spans); got != tt.want {
+				t.Errorf("markSpans %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func testSearchOrder() *domain.Order {
+	return &domain.Order{
+		TrackNumber: "WBILMTESTTRACK",
+		Delivery: domain.Delivery{
+			Name:    "Ivan Moscow",
+			Phone:   "+79161234567",
+			City:    "Moscow",
+			Address: "Moscow street 1",
+			Email:   "moscow@example.com",
+		},
+		Items: []domain.Item{{Name: "Phone case", Brand: "Moscow brand"}},
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	order := testSearchOrder()
+
+	tests := []struct {
+		name    string
+		search  domain.OrderSearch
+		visible *domain.Order
+		want    map[string]string
+	}{
+		{
+			name:    "with PII",
+			search:  domain.OrderSearch{Text: "moscow", PII: true},
+			visible: order,
+			want: map[string]string{
+				"delivery.name":    "Ivan <mark>Moscow</mark>",
+				"delivery.email":   "<mark>moscow</mark>@example.com",
+				"delivery.city":    "<mark>Moscow</mark>",
+				"delivery.address": "<mark>Moscow</mark> street 1",
+				"items[0].brand":   "<mark>Moscow</mark> brand",
+			},
+		},
+		{
+			name:    "without PII",
+			search:  domain.OrderSearch{Text: "moscow"},
+			visible: redact.Order(order),
+			want: map[string]string{
+				"delivery.city":  "<mark>Moscow</mark>",
+				"items[0].brand": "<mark>Moscow</mark> brand",
+			},
+		},
+		{
+			name:    "phone digits",
+			search:  domain.OrderSearch{Text: "916 123", PII: true},
+			visible: order,
+			want:    map[string]string{"delivery.phone": "+7<mark>916123</mark>4567"},
+		},
+		{
+			name:    "phone digits without PII",
+			search:  domain.OrderSearch{Text: "916 123"},
+			visible: redact.Order(order),
+			want:    map[string]string{},
+		},
+		{
+			name:    "track number",
+			search:  domain.OrderSearch{Text: "test"},
+			visible: redact.Order(order),
+			want:    map[string]string{"track_number": "WBILM<mark>TEST</mark>TRACK"},
+		},
+		{
+			name:    "masked despite PII",
+			search:  domain.OrderSearch{Text: "ivan", PII: true},
+			visible: redact.Order(order),
+			want:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlights(order, tt.visible, tt.search)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("highlights %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	memoryidempotency "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_idempotency"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLen      = 255
+)
+
+type idempotencyStore interface {
+	Begin(key, fingerprint string) (*memoryidempotency.Response, error)
+	Complete(key string, response memoryidempotency.Response)
+	Release(key string)
+}
+
+var (
+	ErrInvalidIdempotencyKey = NewProblemError(http.StatusBadRequest, "invalid_idempotency_key",
+		"invalid idempotency key")
+	ErrIdempotencyInProgress = NewProblemError(http.StatusConflict, "idempotency_key_in_progress",
+		"request with this idempotency key is in progress")
+	ErrIdempotencyMismatch = NewProblemError(http.StatusUnprocessableEntity, "idempotency_key_mismatch",
+		"idempotency key reused with a different request")
+)
+
+// serveIdempotent runs handle once per Idempotency-Key of the caller and
+// replays the stored response for retries. Responses handle reports as
+// transient, and 5xx results, are not stored.
+func serveIdempotent(w http.ResponseWriter, r *http.Request, store idempotencyStore, fingerprint string,
+	handle func() (response memoryidempotency.Response, transient bool),
+) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		response, _ := handle()
+		writeStoredResponse(w, response)
+		return
+	}
+	if len(key) > idempotencyKeyMaxLen {
+		WriteProblem(w, r, ErrInvalidIdempotencyKey, "")
+		return
+	}
+	key = idempotencyScope(r) + "\n" + key
+
+	stored, err := store.Begin(key, fingerprint)
+	switch {
+	case errors.Is(err, memoryidempotency.ErrInProgress):
+		WriteProblem(w, r, ErrIdempotencyInProgress, "")
+		return
+	case errors.Is(err, memoryidempotency.ErrFingerprintMismatch):
+		WriteProblem(w, r, ErrIdempotencyMismatch, "")
+		return
+	case stored != nil:
+		w.Header().Set(idempotencyReplayedHeader, "true")
+		writeStoredResponse(w, *stored)
+		return
+	}
+
+	completed := false
+	defer func() {
+		if !completed {
+			store.Release(key)
+		}
+	}()
+	response, transient := handle()
+	if !transient && response.Status < http.StatusInternalServerError {
+		store.Complete(key, response)
+		completed = true
+	}
+	writeStoredResponse(w, response)
+}
+
+func idempotencyScope(r *http.Request) string {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		principal = auth.Anonymous
+	}
+	return principal.ID()
+}
+
+func writeStoredResponse(w http.ResponseWriter, response memoryidempotency.Response) {
+	w.Header().Set("Content-Type", response.ContentType)
+	w.WriteHeader(response.Status)
+	if _, err := w.Write(response.Body); err != nil {
+		log.Printf("http.writeStoredResponse: %v\n", err)
+	}
+}
+
+func requestFingerprint(r *http.Request, body io.Reader) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
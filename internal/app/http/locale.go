@@ -0,0 +1,80 @@
+package http
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	supportedLocales = []language.Tag{
+		language.English, // first tag is the fallback
+		language.Russian,
+		language.German,
+		language.French,
+		language.Spanish,
+		language.Italian,
+	}
+	localeMatcher = language.NewMatcher(supportedLocales)
+
+	dateLayouts = map[language.Base]string{
+		mustBase(language.English): "Jan 2, 2006 15:04 MST",
+		mustBase(language.Russian): "02.01.2006 15:04 MST",
+		mustBase(language.German):  "02.01.2006 15:04 MST",
+		mustBase(language.French):  "02/01/2006 15:04 MST",
+		mustBase(language.Spanish): "02/01/2006 15:04 MST",
+		mustBase(language.Italian): "02/01/2006 15:04 MST",
+	}
+)
+
+// LocaleFormatter formats numbers and dates of HTML pages for an order's locale.
+type LocaleFormatter struct {
+	tag        language.Tag
+	printer    *message.Printer
+	dateLayout string
+}
+
+func NewLocaleFormatter(locale string) LocaleFormatter {
+	requested, _ := language.Parse(locale) // unknown locales fall back to English
+	_, index, _ := localeMatcher.Match(requested)
+	tag := supportedLocales[index]
+	base, _ := tag.Base()
+
+	layout, inMap := dateLayouts[base]
+	if !inMap {
+		layout = dateLayouts[mustBase(language.English)]
+	}
+
+	return LocaleFormatter{
+		tag:        tag,
+		printer:    message.NewPrinter(tag),
+		dateLayout: layout,
+	}
+}
+
+func (f LocaleFormatter) Lang() string {
+	return f.tag.String()
+}
+
+func (f LocaleFormatter) Number(n int) string {
+	return f.printer.Sprint(number.Decimal(n))
+}
+
+func (f LocaleFormatter) Money(amount int, currency string) string {
+	return f.Number(amount) + " " + currency
+}
+
+func (f LocaleFormatter) Date(t time.Time) string {
+	return t.Format(f.dateLayout)
+}
+
+func (f LocaleFormatter) Unix(sec int64) string {
+	return f.Date(time.Unix(sec, 0).UTC())
+}
+
+func mustBase(tag language.Tag) language.Base {
+	base, _ := tag.Base()
+	return base
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleFormatter(t *testing.T) {
+	date := time.Date(2021, time.November, 26, 6, 22, 19, 0, time.UTC)
+	tests := []struct {
+		locale, lang, number, date string
+	}{
+		{"en", "en", "1,234,567", "Nov 26, 2021 06:22 UTC"},
+		{"ru", "ru", "1\u00a0234\u00a0567", "26.11.2021 06:22 UTC"},
+		{"de-DE", "de", "1.234.567", "26.11.2021 06:22 UTC"},
+		{"", "en", "1,234,567", "Nov 26, 2021 06:22 UTC"},
+		{"xx", "en", "1,234,567", "Nov 26, 2021 06:22 UTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			f := NewLocaleFormatter(tt.locale)
+			if got := f.Lang(); got != tt.lang {
+				t.Errorf("Lang() = %q, want %q", got, tt.lang)
+			}
+			if got := f.Number(1234567); got != tt.number {
+				t.Errorf("Number() = %q, want %q", got, tt.number)
+			}
+			if got := f.Date(date); got != tt.date {
+				t.Errorf("Date() = %q, want %q", got, tt.date)
+			}
+			if got := f.Unix(date.Unix()); got != tt.date {
+				t.Errorf("Unix() = %q, want %q", got, tt.date)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	mediaTypeJSON = "application/json"
+	mediaTypeHTML = "text/html"
+)
+
+// negotiateMediaType returns the offer preferred by Accept, the first one on ties.
+func negotiateMediaType(r *http.Request, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return offers[0]
+	}
+
+	best, bestQ, bestSpecificity := offers[0], 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if qs, inMap := params["q"]; inMap {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		for _, offer := range offers {
+			specificity := mediaTypeSpecificity(mediaType, offer)
+			if specificity < 0 {
+				continue
+			}
+			if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+				best, bestQ, bestSpecificity = offer, q, specificity
+			}
+		}
+	}
+
+	return best
+}
+
+func mediaTypeSpecificity(accepted, offer string) int {
+	switch {
+	case accepted == offer:
+		return 2
+	case accepted == "*/*":
+		return 0
+	case strings.HasSuffix(accepted, "/*") &&
+		strings.HasPrefix(offer, strings.TrimSuffix(accepted, "*")):
+		return 1
+	default:
+		return -1
+	}
+}
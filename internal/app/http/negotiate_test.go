@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		name, accept, want string
+	}{
+		{"absent", "", mediaTypeJSON},
+		{"exact", "text/html", mediaTypeHTML},
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", mediaTypeHTML},
+		{"quality", "text/html;q=0.5, application/json", mediaTypeJSON},
+		{"type wildcard", "text/*", mediaTypeHTML},
+		{"any", "*/*", mediaTypeJSON},
+		{"exact beats wildcard", "*/*, text/html", mediaTypeHTML},
+		{"zero quality", "text/html;q=0, */*;q=0.1", mediaTypeJSON},
+		{"malformed quality", "text/html;q=x", mediaTypeJSON},
+		{"unmatched", "image/png", mediaTypeJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if got := negotiateMediaType(r, mediaTypeJSON, mediaTypeHTML); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
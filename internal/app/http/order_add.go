@@ -0,0 +1,359 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memoryidempotency "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_idempotency"
+)
+
+const (
+	addOrderMaxBodyBytes  = 1 << 20
+	addOrdersMaxBodyBytes = 32 << 20
+
+	bulkStatusCreated   = "created"
+	bulkStatusDuplicate = "duplicate"
+	bulkStatusInvalid   = "invalid"
+	bulkStatusFailed    = "failed"
+
+	spoolFilePattern = "orders-bulk-*.ndjson"
+)
+
+type (
+	addOrderUsecase interface {
+		AddOrder(ctx context.Context, order domain.Order) error
+	}
+
+	AddOrderResponse struct {
+		OrderUID string `json:"order_uid"`
+	}
+	AddOrdersBulkResult struct {
+		Line       int                `json:"line"`
+		OrderUID   string             `json:"order_uid,omitempty"`
+		Status     string             `json:"status"`
+		Error      string             `json:"error,omitempty"`
+		Violations []ingest.Violation `json:"violations,omitempty"`
+	}
+	AddOrdersBulkResponse struct {
+		Accepted   int                   `json:"accepted"`
+		Rejected   int                   `json:"rejected"`
+		Duplicates int                   `json:"duplicates"`
+		Results    []AddOrdersBulkResult `json:"results"`
+	}
+
+	AddOrderHandler struct {
+		name        string
+		decoder     *ingest.Decoder
+		usecase     addOrderUsecase
+		idempotency idempotencyStore
+		logger      logger
+	}
+	AddOrdersBulkHandler struct {
+		name        string
+		decoder     *ingest.Decoder
+		usecase     addOrderUsecase
+		idempotency idempotencyStore
+		timeout     time.Duration
+		logger      logger
+	}
+)
+
+var (
+	ErrBodyTooLarge     = NewProblemError(http.StatusRequestEntityTooLarge, "body_too_large", "request body too large")
+	ErrValidationFailed = NewProblemError(http.StatusUnprocessableEntity, "validation_failed", "validation failed")
+)
+
+func NewAddOrderHandler(usecase addOrderUsecase, decoder *ingest.Decoder, idempotency idempotencyStore,
+	name string, logger logger,
+) *AddOrderHandler {
+	return &AddOrderHandler{
+		name:        name,
+		decoder:     decoder,
+		usecase:     usecase,
+		idempotency: idempotency,
+		logger:      logger,
+	}
+}
+
+func NewAddOrdersBulkHandler(usecase addOrderUsecase, decoder *ingest.Decoder, idempotency idempotencyStore,
+	timeout time.Duration, name string, logger logger,
+) *AddOrdersBulkHandler {
+	return &AddOrdersBulkHandler{
+		name:        name,
+		decoder:     decoder,
+		usecase:     usecase,
+		idempotency: idempotency,
+		timeout:     timeout,
+		logger:      logger,
+	}
+}
+
+func (h *AddOrderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r, addOrderMaxBodyBytes)
+	if !ok {
+		return
+	}
+
+	// Hashing a bytes.Reader cannot fail.
+	fingerprint, _ := requestFingerprint(r, bytes.NewReader(body))
+	serveIdempotent(w, r, h.idempotency, fingerprint, func() (memoryidempotency.Response, bool) {
+		return h.addOrder(r, body), false
+	})
+}
+
+func (h *AddOrderHandler) addOrder(r *http.Request, body []byte) memoryidempotency.Response {
+	order, err := h.decoder.Decode(body)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+
+	err = h.usecase.AddOrder(r.Context(), order)
+	switch {
+	case errors.Is(err, domain.ErrOrderAlreadyExists):
+		return problemResponse(r, err, "")
+	case err != nil:
+		loggerFrom(r.Context(), h.logger).Error("addOrderUsecase.AddOrder", zap.Error(err))
+		return problemResponse(r, ErrInternalServerError, "")
+	}
+
+	return jsonResponseBody(http.StatusCreated, AddOrderResponse{OrderUID: order.OrderUID})
+}
+
+// ServeHTTP stores the orders while the body is read. A body with an
+// Idempotency-Key is spooled to a temporary file first to fingerprint it.
+func (h *AddOrdersBulkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := loggerFrom(r.Context(), h.logger)
+	if h.timeout > 0 {
+		deadline := time.Now().Add(h.timeout)
+		rc := http.NewResponseController(w)
+		if err := rc.SetReadDeadline(deadline); err != nil {
+			logger.Error("ResponseController.SetReadDeadline", zap.Error(err))
+		}
+		if err := rc.SetWriteDeadline(deadline); err != nil {
+			logger.Error("ResponseController.SetWriteDeadline", zap.Error(err))
+		}
+		ctx, cancel := context.WithDeadline(r.Context(), deadline)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	var body io.Reader = http.MaxBytesReader(w, r.Body, addOrdersMaxBodyBytes)
+	fingerprint := ""
+	if r.Header.Get(idempotencyKeyHeader) != "" {
+		spooled, sum, err := spoolBody(r, body)
+		var pathErr *fs.PathError
+		switch {
+		case errors.As(err, &pathErr):
+			logger.Error("spoolBody", zap.Error(err))
+			WriteProblem(w, r, ErrInternalServerError, "")
+			return
+		case err != nil:
+			writeBodyError(w, r, err, addOrdersMaxBodyBytes)
+			return
+		}
+		defer removeSpooled(spooled)
+		body, fingerprint = spooled, sum
+	}
+
+	serveIdempotent(w, r, h.idempotency, fingerprint, func() (memoryidempotency.Response, bool) {
+		response := h.addOrders(r.Context(), body)
+		return jsonResponseBody(http.StatusOK, response), response.failed()
+	})
+}
+
+// failed reports lines that were not stored because of a database error or
+// the deadline; a retry may store them.
+func (r AddOrdersBulkResponse) failed() bool {
+	return slices.ContainsFunc(r.Results, func(result AddOrdersBulkResult) bool {
+		return result.Status == bulkStatusFailed
+	})
+}
+
+// addOrders reports every NDJSON line separately, a bad line does not stop
+// the following ones.
+func (h *AddOrdersBulkHandler) addOrders(ctx context.Context, body io.Reader) AddOrdersBulkResponse {
+	response := AddOrdersBulkResponse{
+		Results: []AddOrdersBulkResult{},
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), addOrderMaxBodyBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			response.Rejected++
+			response.Results = append(response.Results, AddOrdersBulkResult{
+				Line:   line,
+				Status: bulkStatusFailed,
+				Error:  err.Error(),
+			})
+			return response
+		}
+
+		result := h.addOrder(ctx, line, data)
+		switch result.Status {
+		case bulkStatusCreated:
+			response.Accepted++
+		case bulkStatusDuplicate:
+			response.Duplicates++
+		default:
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		result := AddOrdersBulkResult{
+			Line:   line + 1,
+			Status: bulkStatusInvalid,
+			Error:  err.Error(),
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			result.Error = fmt.Sprintf("%s: max %d bytes", ErrBodyTooLarge, maxBytesErr.Limit)
+		}
+		response.Rejected++
+		response.Results = append(response.Results, result)
+	}
+
+	return response
+}
+
+func (h *AddOrdersBulkHandler) addOrder(ctx context.Context, line int, data []byte) AddOrdersBulkResult {
+	result := AddOrdersBulkResult{
+		Line: line,
+	}
+
+	order, err := h.decoder.Decode(data)
+	if err != nil {
+		result.Status = bulkStatusInvalid
+		result.Error = err.Error()
+
+		var validationErr *ingest.ValidationError
+		if errors.As(err, &validationErr) {
+			result.Error = ErrValidationFailed.Error()
+			result.Violations = validationErr.Violations
+		}
+		return result
+	}
+	result.OrderUID = order.OrderUID
+
+	err = h.usecase.AddOrder(ctx, order)
+	switch {
+	case errors.Is(err, domain.ErrOrderAlreadyExists):
+		result.Status = bulkStatusDuplicate
+	case err != nil:
+		loggerFrom(ctx, h.logger).Error("addOrderUsecase.AddOrder", zap.Error(err), zap.Int("line", line))
+		result.Status = bulkStatusFailed
+		result.Error = ErrInternalServerError.Error()
+	default:
+		result.Status = bulkStatusCreated
+	}
+
+	return result
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		writeBodyError(w, r, err, limit)
+		return nil, false
+	}
+	return body, true
+}
+
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error, limit int64) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		WriteProblem(w, r, ErrBodyTooLarge, fmt.Sprintf("max %d bytes", limit))
+		return
+	}
+	WriteProblem(w, r, ErrInvalidBody, err.Error())
+}
+
+// spoolBody copies body to a temporary file and returns it rewound with the
+// request fingerprint.
+func spoolBody(r *http.Request, body io.Reader) (*os.File, string, error) {
+	file, err := os.CreateTemp("", spoolFilePattern)
+	if err != nil {
+		return nil, "", err
+	}
+	fingerprint, err := requestFingerprint(r, io.TeeReader(body, file))
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		removeSpooled(file)
+		return nil, "", err
+	}
+	return file, fingerprint, nil
+}
+
+func removeSpooled(file *os.File) {
+	if err := file.Close(); err != nil {
+		log.Printf("http.removeSpooled: %v\n", err)
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		log.Printf("http.removeSpooled: %v\n", err)
+	}
+}
+
+func decodeErrorResponse(r *http.Request, err error) memoryidempotency.Response {
+	var validationErr *ingest.ValidationError
+	if errors.As(err, &validationErr) {
+		return problemResponse(r, err, "")
+	}
+	return problemResponse(r, ErrInvalidBody, err.Error())
+}
+
+func problemResponse(r *http.Request, err error, detail string) memoryidempotency.Response {
+	problem := NewProblem(r, err, detail)
+	body, errMarshal := json.Marshal(problem)
+	if errMarshal != nil {
+		log.Printf("http.problemResponse: %v\n", errMarshal)
+	}
+	return memoryidempotency.Response{
+		Status:      problem.Status,
+		ContentType: mediaTypeProblem,
+		Body:        body,
+	}
+}
+
+func jsonResponseBody(status int, v any) memoryidempotency.Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("http.jsonResponseBody: %v\n", err)
+		return memoryidempotency.Response{
+			Status:      http.StatusInternalServerError,
+			ContentType: mediaTypeProblem,
+			Body: []byte(`{"type":"` + problemTypePrefix + ErrInternalServerError.Code() + `",` +
+				`"title":"internal server error","status":500,"code":"internal_error"}`),
+		}
+	}
+	return memoryidempotency.Response{
+		Status:      status,
+		ContentType: mediaTypeJSON,
+		Body:        body,
+	}
+}
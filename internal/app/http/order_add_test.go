@@ -0,0 +1,316 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memoryidempotency "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_idempotency"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeAddUsecase struct {
+	mx     sync.Mutex
+	orders map[string]domain.Order
+	err    error
+	panics bool
+}
+
+func newFakeAddUsecase() *fakeAddUsecase {
+	return &fakeAddUsecase{orders: map[string]domain.Order{}}
+}
+
+func (u *fakeAddUsecase) AddOrder(_ context.Context, order domain.Order) error {
+	u.mx.Lock()
+	defer u.mx.Unlock()
+
+	if u.panics {
+		panic("add order")
+	}
+	if u.err != nil {
+		return u.err
+	}
+	if _, inMap := u.orders[order.OrderUID]; inMap {
+		return domain.ErrOrderAlreadyExists
+	}
+	u.orders[order.OrderUID] = order
+	return nil
+}
+
+func orderJSON(t *testing.T, order domain.Order) string {
+	t.Helper()
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func postOrder(h http.Handler, path, body, key string, principal *auth.Principal) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(idempotencyKeyHeader, key)
+	}
+	if principal != nil {
+		r = r.WithContext(auth.WithPrincipal(r.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("problem %q: %v", rec.Body, err)
+	}
+	return problem.Code
+}
+
+func TestAddOrderHandler(t *testing.T) {
+	order := fixtures.New(1).Order()
+	invalid := order
+	invalid.OrderUID = "invalid-order"
+	invalid.Delivery.Email = "not an email"
+
+	usecase := newFakeAddUsecase()
+	h := NewAddOrderHandler(usecase, ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10), "addOrder",
+		zap.NewNop())
+
+	tests := []struct {
+		name, body, code string
+		status           int
+	}{
+		{"created", orderJSON(t, order), "", http.StatusCreated},
+		{"duplicate", orderJSON(t, order), "order_already_exists", http.StatusConflict},
+		{"malformed", `{"order_uid":`, "invalid_body", http.StatusBadRequest},
+		{"invalid", orderJSON(t, invalid), "validation_failed", http.StatusUnprocessableEntity},
+		{"too large", strings.Repeat(" ", addOrderMaxBodyBytes+1), "body_too_large", http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postOrder(h, "/orders", tt.body, "", nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.code != "" {
+				if code := problemCode(t, rec); code != tt.code {
+					t.Errorf("code %q, want %q", code, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestAddOrderHandlerIdempotency(t *testing.T) {
+	g := fixtures.New(1)
+	order, other := g.Order(), g.Order()
+	h := NewAddOrderHandler(newFakeAddUsecase(), ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10),
+		"addOrder", zap.NewNop())
+
+	first := postOrder(h, "/orders", orderJSON(t, order), "key-1", nil)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request: %d %s", first.Code, first.Body)
+	}
+
+	retry := postOrder(h, "/orders", orderJSON(t, order), "key-1", nil)
+	if retry.Code != http.StatusCreated || retry.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Fatalf("retry: %d, replayed %q", retry.Code, retry.Header().Get(idempotencyReplayedHeader))
+	}
+	if !bytes.Equal(retry.Body.Bytes(), first.Body.Bytes()) {
+		t.Errorf("replayed body %q, want %q", retry.Body, first.Body)
+	}
+
+	mismatch := postOrder(h, "/orders", orderJSON(t, other), "key-1", nil)
+	if mismatch.Code != http.StatusUnprocessableEntity || problemCode(t, mismatch) != "idempotency_key_mismatch" {
+		t.Fatalf("reused key: %d %s", mismatch.Code, mismatch.Body)
+	}
+
+	long := postOrder(h, "/orders", orderJSON(t, other), strings.Repeat("k", idempotencyKeyMaxLen+1), nil)
+	if long.Code != http.StatusBadRequest {
+		t.Fatalf("long key: %d", long.Code)
+	}
+}
+
+func TestAddOrderHandlerIdempotencyPerPrincipal(t *testing.T) {
+	g := fixtures.New(1)
+	order, other := g.Order(), g.Order()
+	h := NewAddOrderHandler(newFakeAddUsecase(), ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10),
+		"addOrder", zap.NewNop())
+	alice := auth.Principal{Subject: "alice", Method: "api_key", Scopes: []string{auth.ScopeOrdersWrite}}
+	bob := auth.Principal{Subject: "bob", Method: "api_key", Scopes: []string{auth.ScopeOrdersWrite}}
+
+	if rec := postOrder(h, "/orders", orderJSON(t, order), "same-key", &alice); rec.Code != http.StatusCreated {
+		t.Fatalf("alice: %d %s", rec.Code, rec.Body)
+	}
+	rec := postOrder(h, "/orders", orderJSON(t, other), "same-key", &bob)
+	if rec.Code != http.StatusCreated || rec.Header().Get(idempotencyReplayedHeader) != "" {
+		t.Fatalf("bob got %d, replayed %q: %s", rec.Code, rec.Header().Get(idempotencyReplayedHeader), rec.Body)
+	}
+	var response AddOrderResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || response.OrderUID != other.OrderUID {
+		t.Fatalf("bob got the response of alice: %s", rec.Body)
+	}
+}
+
+func TestAddOrderHandlerReleasesKey(t *testing.T) {
+	order := fixtures.New(1).Order()
+	usecase := newFakeAddUsecase()
+	h := NewAddOrderHandler(usecase, ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10), "addOrder",
+		zap.NewNop())
+
+	usecase.err = errors.New("db is down")
+	if rec := postOrder(h, "/orders", orderJSON(t, order), "key-1", nil); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("failed request: %d", rec.Code)
+	}
+
+	usecase.err, usecase.panics = nil, true
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("no panic")
+			}
+		}()
+		postOrder(h, "/orders", orderJSON(t, order), "key-1", nil)
+	}()
+
+	usecase.panics = false
+	if rec := postOrder(h, "/orders", orderJSON(t, order), "key-1", nil); rec.Code != http.StatusCreated {
+		t.Fatalf("retry after a panic: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func bulkBody(t *testing.T, orders ...domain.Order) string {
+	t.Helper()
+	lines := make([]string, 0, len(orders))
+	for _, order := range orders {
+		lines = append(lines, orderJSON(t, order))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func decodeBulk(t *testing.T, rec *httptest.ResponseRecorder) AddOrdersBulkResponse {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var response AddOrdersBulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("response %q: %v", rec.Body, err)
+	}
+	return response
+}
+
+func TestAddOrdersBulkHandler(t *testing.T) {
+	g := fixtures.New(1)
+	first, second := g.Order(), g.Order()
+	body := bulkBody(t, first, second, first) + "\n\n{\"order_uid\": 1}\n"
+
+	for _, key := range []string{"", "bulk-key"} {
+		t.Run("key "+key, func(t *testing.T) {
+			h := NewAddOrdersBulkHandler(newFakeAddUsecase(), ingest.NewDecoder(),
+				memoryidempotency.New(time.Hour, 10), time.Minute, "addOrdersBulk", zap.NewNop())
+
+			response := decodeBulk(t, postOrder(h, "/orders:bulk", body, key, nil))
+			if response.Accepted != 2 || response.Duplicates != 1 || response.Rejected != 1 {
+				t.Fatalf("response %+v", response)
+			}
+			wantStatuses := []string{bulkStatusCreated, bulkStatusCreated, bulkStatusDuplicate, bulkStatusInvalid}
+			wantLines := []int{1, 2, 3, 5}
+			for i, result := range response.Results {
+				if result.Status != wantStatuses[i] || result.Line != wantLines[i] {
+					t.Errorf("result %d: %+v", i, result)
+				}
+			}
+
+			if key == "" {
+				return
+			}
+			retry := postOrder(h, "/orders:bulk", body, key, nil)
+			if retry.Header().Get(idempotencyReplayedHeader) != "true" {
+				t.Fatal("bulk retry is not replayed")
+			}
+			if replayed := decodeBulk(t, retry); replayed.Accepted != 2 {
+				t.Errorf("replayed %+v", replayed)
+			}
+		})
+	}
+}
+
+func TestAddOrdersBulkHandlerTooLarge(t *testing.T) {
+	order := fixtures.New(1).Order()
+	line := orderJSON(t, order) + "\n"
+	body := line + strings.Repeat("\n", addOrdersMaxBodyBytes)
+
+	usecase := newFakeAddUsecase()
+	h := NewAddOrdersBulkHandler(usecase, ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10), time.Minute,
+		"addOrdersBulk", zap.NewNop())
+
+	rec := postOrder(h, "/orders:bulk", body, "bulk-key", nil)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("with a key: %d, want 413", rec.Code)
+	}
+	if len(usecase.orders) != 0 {
+		t.Fatal("orders stored from a rejected body")
+	}
+
+	response := decodeBulk(t, postOrder(h, "/orders:bulk", body, "", nil))
+	if response.Accepted != 1 || response.Rejected != 1 {
+		t.Fatalf("without a key: %+v", response)
+	}
+	if last := response.Results[len(response.Results)-1]; !strings.Contains(last.Error, ErrBodyTooLarge.Error()) {
+		t.Errorf("last result %+v", last)
+	}
+}
+
+func TestAddOrdersBulkHandlerTimeout(t *testing.T) {
+	g := fixtures.New(1)
+	h := NewAddOrdersBulkHandler(newFakeAddUsecase(), ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10),
+		time.Nanosecond, "addOrdersBulk", zap.NewNop())
+
+	response := decodeBulk(t, postOrder(h, "/orders:bulk", bulkBody(t, g.Order(), g.Order()), "", nil))
+	if response.Accepted != 0 || response.Rejected != 1 || response.Results[0].Status != bulkStatusFailed {
+		t.Fatalf("response %+v", response)
+	}
+}
+
+func TestAddOrdersBulkHandlerReleasesKeyOnFailure(t *testing.T) {
+	g := fixtures.New(1)
+	body := bulkBody(t, g.Order(), g.Order())
+	usecase := newFakeAddUsecase()
+	h := NewAddOrdersBulkHandler(usecase, ingest.NewDecoder(), memoryidempotency.New(time.Hour, 10), time.Minute,
+		"addOrdersBulk", zap.NewNop())
+
+	usecase.err = errors.New("db is down")
+	rec := postOrder(h, "/orders:bulk", body, "bulk-key", nil)
+	if response := decodeBulk(t, rec); response.Rejected != 2 || response.Results[0].Status != bulkStatusFailed {
+		t.Fatalf("failed request: %+v", response)
+	}
+
+	usecase.err = nil
+	retry := postOrder(h, "/orders:bulk", body, "bulk-key", nil)
+	if retry.Header().Get(idempotencyReplayedHeader) != "" {
+		t.Fatal("failed lines replayed")
+	}
+	if response := decodeBulk(t, retry); response.Accepted != 2 {
+		t.Fatalf("retry: %+v", response)
+	}
+
+	replay := postOrder(h, "/orders:bulk", body, "bulk-key", nil)
+	if replay.Header().Get(idempotencyReplayedHeader) != "true" {
+		t.Error("successful retry is not stored")
+	}
+}
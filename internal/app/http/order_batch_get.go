@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const batchGetMaxBodyBytes = 1 << 20
+
+type (
+	batchGetOrdersUsecase interface {
+		GetOrders(ctx context.Context, orderUIDs []string) ([]*domain.Order, []string, error)
+	}
+
+	BatchGetOrdersRequest struct {
+		OrderUIDs []string `json:"order_uids"`
+	}
+	BatchGetOrdersResponse struct {
+		Orders  []*domain.Order `json:"orders"`
+		Missing []string        `json:"missing"`
+	}
+
+	BatchGetOrdersHandler struct {
+		name    string
+		limit   int
+		usecase batchGetOrdersUsecase
+		logger  logger
+	}
+)
+
+var (
+	ErrInvalidBody  = NewProblemError(http.StatusBadRequest, "invalid_body", "invalid request body")
+	ErrTooManyUIDs  = NewProblemError(http.StatusBadRequest, "too_many_order_uids", "too many order uids")
+	ErrEmptyUIDList = NewProblemError(http.StatusBadRequest, "empty_order_uids", "order uids list is empty")
+)
+
+func NewBatchGetOrdersHandler(usecase batchGetOrdersUsecase, limit int, name string, logger logger) *BatchGetOrdersHandler {
+	return &BatchGetOrdersHandler{
+		name:    name,
+		limit:   limit,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *BatchGetOrdersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := BatchGetOrdersRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, batchGetMaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		WriteProblem(w, r, ErrInvalidBody, err.Error())
+		return
+	}
+
+	if len(req.OrderUIDs) == 0 {
+		WriteProblem(w, r, ErrEmptyUIDList, "")
+		return
+	}
+	if len(req.OrderUIDs) > h.limit {
+		WriteProblem(w, r, ErrTooManyUIDs, fmt.Sprintf("max %d", h.limit))
+		return
+	}
+	for i, orderUID := range req.OrderUIDs {
+		if !domain.ValidOrderUID(orderUID) {
+			WriteProblem(w, r, ErrInvalidParameter, fmt.Sprintf("order_uids[%d]", i))
+			return
+		}
+	}
+
+	orders, missing, err := h.usecase.GetOrders(r.Context(), req.OrderUIDs)
+	if err != nil {
+		loggerFrom(r.Context(), h.logger).Error("batchGetOrdersUsecase.GetOrders", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	for i, order := range orders {
+		orders[i] = redact.Visible(r.Context(), order)
+	}
+
+	response, err := json.Marshal(BatchGetOrdersResponse{
+		Orders:  orders,
+		Missing: missing,
+	})
+	if err != nil {
+		loggerFrom(r.Context(), h.logger).Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	GetSuccessResponseWithBody(w, response)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeBatchGetUsecase struct {
+	orders map[string]*domain.Order
+}
+
+func (u fakeBatchGetUsecase) GetOrders(_ context.Context, orderUIDs []string) ([]*domain.Order, []string, error) {
+	var orders []*domain.Order
+	missing := []string{}
+	for _, uid := range orderUIDs {
+		if order, inMap := u.orders[uid]; inMap {
+			orders = append(orders, order)
+		} else {
+			missing = append(missing, uid)
+		}
+	}
+	return orders, missing, nil
+}
+
+func TestBatchGetOrdersHandler(t *testing.T) {
+	order := fixtures.New(1).Order()
+	h := NewBatchGetOrdersHandler(fakeBatchGetUsecase{orders: map[string]*domain.Order{order.OrderUID: &order}},
+		2, "batchGet", zap.NewNop())
+
+	tests := []struct {
+		name, body, code string
+		status           int
+	}{
+		{"found and missing", `{"order_uids": ["` + order.OrderUID + `", "missing-order"]}`, "", http.StatusOK},
+		{"malformed", `{"order_uids": [`, "invalid_body", http.StatusBadRequest},
+		{"unknown field", `{"uids": ["missing-order"]}`, "invalid_body", http.StatusBadRequest},
+		{"empty", `{"order_uids": []}`, "empty_order_uids", http.StatusBadRequest},
+		{"too many", `{"order_uids": ["order-001", "order-002", "order-003"]}`, "too_many_order_uids",
+			http.StatusBadRequest},
+		{"invalid uid", `{"order_uids": ["bad uid"]}`, "invalid_parameter", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/batch-get", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.code == "" {
+				return
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("problem: %v", err)
+			}
+			if problem.Code != tt.code {
+				t.Errorf("code %q, want %q", problem.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestBatchGetOrdersHandlerMasksPII(t *testing.T) {
+	order := fixtures.New(1).Order()
+	h := NewBatchGetOrdersHandler(fakeBatchGetUsecase{orders: map[string]*domain.Order{order.OrderUID: &order}},
+		10, "batchGet", zap.NewNop())
+	body := `{"order_uids": ["` + order.OrderUID + `", "missing-order"]}`
+
+	for _, withPII := range []bool{false, true} {
+		r := httptest.NewRequest(http.MethodPost, "/orders/batch-get", strings.NewReader(body))
+		if withPII {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Anonymous))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+
+		var response BatchGetOrdersResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("response: %v", err)
+		}
+		if len(response.Orders) != 1 || len(response.Missing) != 1 || response.Missing[0] != "missing-order" {
+			t.Fatalf("response %+v", response)
+		}
+		if got := response.Orders[0].Delivery.Phone == order.Delivery.Phone; got != withPII {
+			t.Errorf("with PII %t: phone %q returned as is: %t", withPII, response.Orders[0].Delivery.Phone, got)
+		}
+	}
+	if order.Delivery.Phone == "" || strings.Contains(order.Delivery.Phone, "*") {
+		t.Errorf("the stored order was modified: %q", order.Delivery.Phone)
+	}
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	paramFields      = "fields"
+	paramInclude     = "include"
+	paramItemsLimit  = "items_limit"
+	paramItemsOffset = "items_offset"
+
+	includeItems   = "items"
+	fieldSeparator = "."
+	maxItemsLimit  = 1000
+
+	headerItemsTotal = "X-Items-Total"
+)
+
+type orderView struct {
+	// fields maps top-level fields to their selected fields, nil for all.
+	fields      map[string][]string
+	items       bool
+	paginated   bool
+	itemsLimit  int
+	itemsOffset int
+}
+
+var (
+	orderFieldNames = jsonFieldNames(reflect.TypeFor[domain.Order]())
+
+	errUnknownInclude = fmt.Errorf("%s supports only %q", paramInclude, includeItems)
+	errItemsInFields  = fmt.Errorf("items are selected with %s=%s", paramInclude, includeItems)
+)
+
+// parseOrderView returns ok false when the whole order is asked for.
+func parseOrderView(query url.Values) (view orderView, ok bool, err error) {
+	fields, include := query.Get(paramFields), query.Get(paramInclude)
+	limit, offset := query.Get(paramItemsLimit), query.Get(paramItemsOffset)
+	if fields == "" && include == "" && limit == "" && offset == "" {
+		return orderView{}, false, nil
+	}
+
+	if fields != "" {
+		if view.fields, err = parseFields(fields); err != nil {
+			return orderView{}, false, err
+		}
+	}
+	for name := range strings.SplitSeq(include, ",") {
+		switch name {
+		case "":
+		case includeItems:
+			view.items = true
+		default:
+			return orderView{}, false, errUnknownInclude
+		}
+	}
+
+	if limit != "" || offset != "" {
+		view.items, view.paginated = true, true
+		if view.itemsLimit, err = intParam(query, paramItemsLimit, maxItemsLimit, 1, maxItemsLimit); err != nil {
+			return orderView{}, false, err
+		}
+		if view.itemsOffset, err = intParam(query, paramItemsOffset, 0, 0, math.MaxInt32); err != nil {
+			return orderView{}, false, err
+		}
+	}
+
+	return view, true, nil
+}
+
+func parseFields(list string) (map[string][]string, error) {
+	fields := make(map[string][]string)
+	for name := range strings.SplitSeq(list, ",") {
+		parent, child, nested := strings.Cut(strings.TrimSpace(name), fieldSeparator)
+		if parent == includeItems {
+			return nil, errItemsInFields
+		}
+		children, known := orderFieldNames[parent]
+		if !known || nested && !children[child] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+
+		selected, seen := fields[parent]
+		switch {
+		case !nested:
+			fields[parent] = nil
+		case !seen || selected != nil:
+			fields[parent] = append(selected, child)
+		}
+	}
+	return fields, nil
+}
+
+// parts leaves paginated items to be loaded on their own.
+func (v orderView) parts() domain.OrderParts {
+	_, delivery := v.fields["delivery"]
+	_, payment := v.fields["payment"]
+	return domain.OrderParts{
+		Delivery: v.fields == nil || delivery,
+		Payment:  v.fields == nil || payment,
+		Items:    v.items && !v.paginated,
+	}
+}
+
+func (v orderView) render(order *domain.Order) ([]byte, error) {
+	full, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(full, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(all))
+	for name, value := range all {
+		if name == includeItems {
+			continue
+		}
+		children, ok := v.fields[name]
+		switch {
+		case v.fields != nil && !ok:
+			continue
+		case children != nil:
+			if value, err = pick(value, children); err != nil {
+				return nil, err
+			}
+		}
+		selected[name] = value
+	}
+	if v.items {
+		selected[includeItems] = all[includeItems]
+	}
+
+	return json.Marshal(selected)
+}
+
+func pick(object json.RawMessage, names []string) (json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(object, &all); err != nil {
+		return nil, err
+	}
+	picked := make(map[string]json.RawMessage, len(names))
+	for _, name := range names {
+		picked[name] = all[name]
+	}
+	return json.Marshal(picked)
+}
+
+func jsonFieldNames(t reflect.Type) map[string]map[string]bool {
+	names := make(map[string]map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		var children map[string]bool
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			children = make(map[string]bool)
+			for child := range jsonFieldNames(field.Type) {
+				children[child] = true
+			}
+		}
+		names[name] = children
+	}
+	return names
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func TestParseOrderView(t *testing.T) {
+	tests := []struct {
+		query string
+		want  orderView
+		ok    bool
+	}{
+		{"", orderView{}, false},
+		{"fields=order_uid,delivery.city", orderView{fields: map[string][]string{
+			"order_uid": nil, "delivery": {"city"}}}, true},
+		{"fields=delivery.city,delivery.zip", orderView{fields: map[string][]string{
+			"delivery": {"city", "zip"}}}, true},
+		{"fields=delivery,delivery.city", orderView{fields: map[string][]string{"delivery": nil}}, true},
+		{"fields=delivery.city,delivery", orderView{fields: map[string][]string{"delivery": nil}}, true},
+		{"include=items", orderView{items: true}, true},
+		{"items_limit=5", orderView{items: true, paginated: true, itemsLimit: 5}, true},
+		{"items_offset=10", orderView{items: true, paginated: true, itemsLimit: maxItemsLimit, itemsOffset: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			view, ok, err := parseOrderView(query)
+			if err != nil {
+				t.Fatalf("parseOrderView: %v", err)
+			}
+			if ok != tt.ok || !reflect.DeepEqual(view, tt.want) {
+				t.Errorf("view %+v (ok %v), want %+v (ok %v)", view, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseOrderViewErrors(t *testing.T) {
+	for _, query := range []string{
+		"fields=unknown",
+		"fields=delivery.unknown",
+		"fields=order_uid.city",
+		"fields=items",
+		"include=payment",
+		"items_limit=0",
+		"items_limit=1001",
+		"items_offset=-1",
+		"items_limit=x",
+	} {
+		t.Run(query, func(t *testing.T) {
+			values, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := parseOrderView(values); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestOrderViewParts(t *testing.T) {
+	tests := []struct {
+		name string
+		view orderView
+		want domain.OrderParts
+	}{
+		{"all fields", orderView{}, domain.OrderParts{Delivery: true, Payment: true}},
+		{"all fields with items", orderView{items: true}, domain.OrderParts{Delivery: true, Payment: true, Items: true}},
+		{"paginated items", orderView{items: true, paginated: true}, domain.OrderParts{Delivery: true, Payment: true}},
+		{"order row only", orderView{fields: map[string][]string{"order_uid": nil}}, domain.OrderParts{}},
+		{"delivery", orderView{fields: map[string][]string{"delivery": {"city"}}}, domain.OrderParts{Delivery: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.view.parts(); got != tt.want {
+				t.Errorf("parts %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderViewRender(t *testing.T) {
+	order := fixtures.New(10).Order()
+
+	tests := []struct {
+		name string
+		view orderView
+		want []string
+	}{
+		{"selected fields", orderView{fields: map[string][]string{"order_uid": nil, "delivery": {"city"}}},
+			[]string{"delivery", "order_uid"}},
+		{"items", orderView{fields: map[string][]string{"order_uid": nil}, items: true},
+			[]string{"items", "order_uid"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.view.render(&order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("rendered %s, want only %v", body, tt.want)
+			}
+			for _, name := range tt.want {
+				if _, ok := got[name]; !ok {
+					t.Errorf("rendered %s without %s", body, name)
+				}
+			}
+		})
+	}
+
+	body, err := orderView{fields: map[string][]string{"delivery": {"city"}}}.render(&order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Delivery map[string]string `json:"delivery"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"city": order.Delivery.City}; !reflect.DeepEqual(got.Delivery, want) {
+		t.Errorf("delivery %v, want %v", got.Delivery, want)
+	}
+
+	body, err = orderView{}.render(&order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(body, &all); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := all["items"]; ok || len(all) != len(orderFieldNames)-1 {
+		t.Errorf("rendered %d fields, want all %d but items", len(all), len(orderFieldNames)-1)
+	}
+}
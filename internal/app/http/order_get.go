@@ -1,22 +1,37 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"regexp"
+	"strconv"
 
 	"go.uber.org/zap"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
 	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memoryorderbody "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order_body"
 )
 
 type (
 	getOrderUsecase interface {
 		GetOrder(ctx context.Context, orderUID string) (*domain.Order, error)
 	}
+	orderPartsUsecase interface {
+		GetOrder(ctx context.Context, orderUID string, parts domain.OrderParts) (*domain.Order, error)
+		GetOrderItems(ctx context.Context, orderUID string, limit, offset int) ([]domain.Item, int, error)
+	}
+	orderBodyCache interface {
+		Get(key string) *memoryorderbody.Entry
+		Put(key string, entry *memoryorderbody.Entry)
+	}
+	htmlRenderer interface {
+		RenderOrder(w http.ResponseWriter, order *domain.Order) error
+		RenderError(w http.ResponseWriter, status int, err error) error
+	}
 	logger interface {
 		Info(msg string, fields ...zap.Field)
 		Error(msg string, fields ...zap.Field)
@@ -25,28 +40,53 @@ type (
 	GetOrderHandler struct {
 		name            string
 		getOrderUsecase getOrderUsecase
+		partsUsecase    orderPartsUsecase
+		renderer        htmlRenderer
+		bodies          orderBodyCache
+		cacheControl    string
 		logger          logger
 	}
 )
 
 var (
-	re                     = regexp.MustCompile("^[a-zA-Z0-9_-]{8,64}$")
-	ErrInvalidParameter    = errors.New("invalid parameter")
-	ErrInternalServerError = errors.New("internal server error")
+	ErrInvalidParameter    = NewProblemError(http.StatusBadRequest, "invalid_parameter", "invalid parameter")
+	ErrInternalServerError = NewProblemError(http.StatusInternalServerError, "internal_error", "internal server error")
+	ErrMethodNotAllowed    = NewProblemError(http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed")
 )
 
-func NewGetOrderHandler(usecase getOrderUsecase, name string, logger logger) *GetOrderHandler {
+func NewGetOrderHandler(usecase getOrderUsecase, partsUsecase orderPartsUsecase, renderer htmlRenderer,
+	bodies orderBodyCache, cacheControl, name string, logger logger,
+) *GetOrderHandler {
 	return &GetOrderHandler{
 		name:            name,
 		getOrderUsecase: usecase,
+		partsUsecase:    partsUsecase,
+		renderer:        renderer,
+		bodies:          bodies,
+		cacheControl:    cacheControl,
 		logger:          logger,
 	}
 }
 
 func (h *GetOrderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := loggerFrom(r.Context(), h.logger)
+	w.Header().Add("Vary", "Accept, Authorization, X-API-Key")
+	asHTML := negotiateMediaType(r, mediaTypeJSON, mediaTypeHTML) == mediaTypeHTML
+
 	orderUID := r.PathValue(definitions.ParamOrderUID)
-	if !re.MatchString(orderUID) {
-		GetErrorResponse(w, http.StatusBadRequest, ErrInvalidParameter, "")
+	if !domain.ValidOrderUID(orderUID) {
+		h.errorResponse(w, r, asHTML, ErrInvalidParameter)
+		return
+	}
+
+	view, partial, err := parseOrderView(r.URL.Query())
+	if err != nil && !asHTML {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+	// The HTML page always shows the whole order.
+	if partial && !asHTML {
+		h.servePartial(w, r, orderUID, view)
 		return
 	}
 
@@ -54,20 +94,116 @@ func (h *GetOrderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	order, err := h.getOrderUsecase.GetOrder(ctx, orderUID)
 	if err != nil {
 		if errors.Is(err, domain.ErrOrderNotFound) {
-			h.logger.Info("order not found", zap.String("orderUID", orderUID))
-			GetErrorResponse(w, http.StatusNotFound, err, "")
+			logger.Info("order not found", zap.String("orderUID", orderUID))
+			h.errorResponse(w, r, asHTML, err)
+			return
+		}
+		logger.Error("getOrderUsecase.GetOrder", zap.Error(err))
+		h.errorResponse(w, r, asHTML, ErrInternalServerError)
+		return
+	}
+	if h.cacheControl != "" {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+
+	if asHTML {
+		if err := h.renderer.RenderOrder(w, redact.Visible(ctx, order)); err != nil {
+			logger.Error("renderer.RenderOrder", zap.Error(err))
+			WriteProblem(w, r, ErrInternalServerError, "")
+		}
+		return
+	}
+
+	body, err := h.orderBody(order, !redact.PIIAllowed(ctx))
+	if err != nil {
+		logger.Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaTypeJSON)
+	w.Header().Set("ETag", body.ETag)
+	http.ServeContent(w, r, "", order.LastModified(), bytes.NewReader(body.Body))
+}
+
+func (h *GetOrderHandler) servePartial(w http.ResponseWriter, r *http.Request, orderUID string, view orderView) {
+	ctx := r.Context()
+	logger := loggerFrom(ctx, h.logger)
+	order, err := h.partsUsecase.GetOrder(ctx, orderUID, view.parts())
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			logger.Info("order not found", zap.String("orderUID", orderUID))
+			WriteProblem(w, r, err, "")
 			return
 		}
-		h.logger.Error("getOrderUsecase.GetOrder", zap.Error(err))
-		GetErrorResponse(w, http.StatusInternalServerError, ErrInternalServerError, "")
+		logger.Error("partsUsecase.GetOrder", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
 		return
 	}
 
-	response, err := json.Marshal(order)
+	visible := *redact.Visible(ctx, order)
+	if view.paginated {
+		items, total, err := h.partsUsecase.GetOrderItems(ctx, orderUID, view.itemsLimit, view.itemsOffset)
+		if err != nil {
+			logger.Error("partsUsecase.GetOrderItems", zap.Error(err))
+			WriteProblem(w, r, ErrInternalServerError, "")
+			return
+		}
+		visible.Items = items
+		w.Header().Set(headerItemsTotal, strconv.Itoa(total))
+	}
+
+	body, err := view.render(&visible)
 	if err != nil {
-		h.logger.Error("json.Marshal", zap.Error(err))
-		GetErrorResponse(w, http.StatusInternalServerError, ErrInternalServerError, "")
+		logger.Error("orderView.render", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
 		return
 	}
-	GetSuccessResponseWithBody(w, response)
+
+	if h.cacheControl != "" {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+	w.Header().Set("Content-Type", mediaTypeJSON)
+	w.Header().Set("ETag", strongETag(body))
+	http.ServeContent(w, r, "", order.LastModified(), bytes.NewReader(body))
+}
+
+func (h *GetOrderHandler) orderBody(order *domain.Order, masked bool) (*memoryorderbody.Entry, error) {
+	key := order.OrderUID
+	if masked {
+		key += ":masked"
+	}
+	if entry := h.bodies.Get(key); entry != nil && entry.Source == order {
+		return entry, nil
+	}
+
+	visible := order
+	if masked {
+		visible = redact.Order(order)
+	}
+	body, err := json.Marshal(visible)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &memoryorderbody.Entry{
+		Source: order,
+		Body:   body,
+		ETag:   strongETag(body),
+	}
+	h.bodies.Put(key, entry)
+
+	return entry, nil
+}
+
+func (h *GetOrderHandler) errorResponse(w http.ResponseWriter, r *http.Request, asHTML bool, err error) {
+	if asHTML {
+		problem := problemFor(err)
+		errRender := h.renderer.RenderError(w, problem.Status(), problem)
+		if errRender == nil {
+			return
+		}
+		loggerFrom(r.Context(), h.logger).Error("renderer.RenderError", zap.Error(errRender))
+	}
+	WriteProblem(w, r, err, "")
 }
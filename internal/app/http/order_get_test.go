@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memoryorderbody "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order_body"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeGetUsecase struct {
+	orders map[string]*domain.Order
+}
+
+func (u fakeGetUsecase) GetOrder(_ context.Context, orderUID string) (*domain.Order, error) {
+	if order, inMap := u.orders[orderUID]; inMap {
+		return order, nil
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+type fakePartsUsecase struct {
+	fakeGetUsecase
+}
+
+func (u fakePartsUsecase) GetOrder(ctx context.Context, orderUID string, _ domain.OrderParts) (*domain.Order, error) {
+	return u.fakeGetUsecase.GetOrder(ctx, orderUID)
+}
+
+func (u fakePartsUsecase) GetOrderItems(_ context.Context, orderUID string, limit, offset int) ([]domain.Item, int, error) {
+	order, inMap := u.orders[orderUID]
+	if !inMap {
+		return nil, 0, domain.ErrOrderNotFound
+	}
+	items := order.Items[min(offset, len(order.Items)):]
+	return items[:min(limit, len(items))], len(order.Items), nil
+}
+
+func newTestGetOrderHandler(t *testing.T, orders ...*domain.Order) *GetOrderHandler {
+	t.Helper()
+	renderer, err := NewHTMLRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usecase := fakeGetUsecase{orders: map[string]*domain.Order{}}
+	for _, order := range orders {
+		usecase.orders[order.OrderUID] = order
+	}
+	return NewGetOrderHandler(usecase, fakePartsUsecase{usecase}, renderer, memoryorderbody.New(10),
+		"private, max-age=300", "getOrder", zap.NewNop())
+}
+
+func getOrder(h http.Handler, path string, principal *auth.Principal, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.SetPathValue("order_uid", strings.Split(strings.TrimPrefix(path, "/order/"), "?")[0])
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	if principal != nil {
+		r = r.WithContext(auth.WithPrincipal(r.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestGetOrderHandlerConditionalGet(t *testing.T) {
+	order := fixtures.New(1).Order()
+	h := newTestGetOrderHandler(t, &order)
+	path := "/order/" + order.OrderUID
+
+	rec := getOrder(h, path, &auth.Anonymous)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Cache-Control") != "private, max-age=300" ||
+		!strings.Contains(rec.Header().Get("Vary"), "Authorization") {
+		t.Fatalf("headers %v", rec.Header())
+	}
+	var got domain.Order
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.OrderUID != order.OrderUID {
+		t.Fatalf("body %s: %v", rec.Body, err)
+	}
+
+	rec = getOrder(h, path, &auth.Anonymous, "If-None-Match", etag)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("status %d, body %q; want 304", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("304 ETag %q", rec.Header().Get("ETag"))
+	}
+
+	rec = getOrder(h, path, &auth.Anonymous, "If-None-Match", `"stale"`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d for a stale ETag", rec.Code)
+	}
+}
+
+func TestGetOrderHandlerLastModified(t *testing.T) {
+	order := fixtures.New(1).Order()
+	order.DateCreated = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	h := newTestGetOrderHandler(t, &order)
+
+	for _, path := range []string{"/order/" + order.OrderUID, "/order/" + order.OrderUID + "?fields=order_uid"} {
+		t.Run(path, func(t *testing.T) {
+			order.UpdatedAt = time.Time{}
+			rec := getOrder(h, path, &auth.Anonymous)
+			if got := rec.Header().Get("Last-Modified"); got != order.DateCreated.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified %q, want the creation date", got)
+			}
+
+			order.UpdatedAt = order.DateCreated.Add(time.Hour)
+			rec = getOrder(h, path, &auth.Anonymous, "If-Modified-Since", order.DateCreated.Format(http.TimeFormat))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d for an order anonymised since", rec.Code)
+			}
+			if got := rec.Header().Get("Last-Modified"); got != order.UpdatedAt.Format(http.TimeFormat) {
+				t.Errorf("Last-Modified %q, want the update date", got)
+			}
+
+			rec = getOrder(h, path, &auth.Anonymous, "If-Modified-Since", order.UpdatedAt.Format(http.TimeFormat))
+			if rec.Code != http.StatusNotModified {
+				t.Errorf("status %d, want 304", rec.Code)
+			}
+		})
+	}
+}
+
+func TestGetOrderHandlerETagDependsOnMasking(t *testing.T) {
+	order := fixtures.New(1).Order()
+	h := newTestGetOrderHandler(t, &order)
+	path := "/order/" + order.OrderUID
+	reader := auth.Principal{Subject: "support", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}}
+
+	full := getOrder(h, path, &auth.Anonymous)
+	masked := getOrder(h, path, &reader)
+	if full.Header().Get("ETag") == masked.Header().Get("ETag") {
+		t.Fatal("masked and full bodies share an ETag")
+	}
+	if strings.Contains(masked.Body.String(), order.Delivery.Email) {
+		t.Error("masked body has the email")
+	}
+
+	rec := getOrder(h, path, &reader, "If-None-Match", full.Header().Get("ETag"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: the full body ETag matched the masked body", rec.Code)
+	}
+}
+
+func TestGetOrderHandlerBodyCache(t *testing.T) {
+	order := fixtures.New(1).Order()
+	h := newTestGetOrderHandler(t, &order)
+
+	first, err := h.orderBody(&order, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := h.orderBody(&order, false); again != first {
+		t.Error("body is serialized again for the same order")
+	}
+
+	changed := order
+	changed.Delivery.City = "Kazan"
+	replaced, _ := h.orderBody(&changed, false)
+	if replaced == first || replaced.ETag == first.ETag {
+		t.Error("cached body is served for a replaced order")
+	}
+}
+
+func TestGetOrderHandlerPartialConditionalGet(t *testing.T) {
+	order := fixtures.New(1, fixtures.WithItemsRange(3, 3)).Order()
+	h := newTestGetOrderHandler(t, &order)
+	path := "/order/" + order.OrderUID + "?fields=order_uid,delivery.city&items_limit=2"
+
+	rec := getOrder(h, path, &auth.Anonymous)
+	if rec.Code != http.StatusOK || rec.Header().Get(headerItemsTotal) != "3" {
+		t.Fatalf("status %d, headers %v", rec.Code, rec.Header())
+	}
+	rec = getOrder(h, path, &auth.Anonymous, "If-None-Match", rec.Header().Get("ETag"))
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status %d, want 304", rec.Code)
+	}
+}
+
+func TestGetOrderHandlerErrors(t *testing.T) {
+	h := newTestGetOrderHandler(t)
+
+	tests := []struct {
+		path, code string
+		status     int
+	}{
+		{"/order/bad%20uid", "invalid_parameter", http.StatusBadRequest},
+		{"/order/b563feb7b2b84b6test", "order_not_found", http.StatusNotFound},
+		{"/order/b563feb7b2b84b6test?include=payment", "invalid_parameter", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := getOrder(h, tt.path, &auth.Anonymous)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if code := problemCode(t, rec); code != tt.code {
+				t.Errorf("code %q, want %q", code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/hub"
+)
+
+const (
+	mediaTypeEventStream = "text/event-stream"
+
+	headerLastEventID = "Last-Event-ID"
+	paramLastEventID  = "last_event_id"
+
+	streamHeartbeat    = 15 * time.Second
+	streamWriteTimeout = 10 * time.Second
+	streamRetry        = 3 * time.Second
+	streamEventOrder   = "order"
+)
+
+type (
+	orderFeed interface {
+		Subscribe(after uint64, match func(order *domain.Order) bool) (*hub.Subscription, []hub.Event, error)
+	}
+
+	OrderStreamMessage struct {
+		ID    uint64        `json:"id"`
+		Order *domain.Order `json:"order"`
+	}
+
+	OrderStreamHandler struct {
+		name   string
+		feed   orderFeed
+		logger logger
+	}
+	OrderWebSocketHandler struct {
+		name   string
+		feed   orderFeed
+		logger logger
+	}
+)
+
+var (
+	ErrShuttingDown    = NewProblemError(http.StatusServiceUnavailable, "shutting_down", "server is shutting down")
+	ErrUpgradeRequired = NewProblemError(http.StatusUpgradeRequired, "upgrade_required", "websocket upgrade required")
+)
+
+func NewOrderStreamHandler(feed orderFeed, name string, logger logger) *OrderStreamHandler {
+	return &OrderStreamHandler{
+		name:   name,
+		feed:   feed,
+		logger: logger,
+	}
+}
+
+func NewOrderWebSocketHandler(feed orderFeed, name string, logger logger) *OrderWebSocketHandler {
+	return &OrderWebSocketHandler{
+		name:   name,
+		feed:   feed,
+		logger: logger,
+	}
+}
+
+func (h *OrderStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := loggerFrom(r.Context(), h.logger)
+
+	after := r.Header.Get(headerLastEventID)
+	if after == "" {
+		after = r.URL.Query().Get(paramLastEventID)
+	}
+	sub, replay, ok := subscribe(w, r, h.feed, after)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Error("ResponseController.SetWriteDeadline", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", mediaTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(write func() error) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			logger.Error("ResponseController.SetWriteDeadline", zap.Error(err))
+		}
+		if err := write(); err != nil {
+			logger.Info("order stream write", zap.Error(err))
+			return false
+		}
+		if err := rc.Flush(); err != nil {
+			logger.Info("order stream flush", zap.Error(err))
+			return false
+		}
+		return true
+	}
+
+	if !send(func() error {
+		_, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+		return err
+	}) {
+		return
+	}
+	for _, event := range replay {
+		if !send(func() error { return writeSSEEvent(r.Context(), w, event) }) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if !send(func() error {
+				_, err := w.Write([]byte(": ping\n\n"))
+				return err
+			}) {
+				return
+			}
+		case event, open := <-sub.Events():
+			if !open {
+				logStreamEnd(logger, sub.Err())
+				return
+			}
+			if !send(func() error { return writeSSEEvent(r.Context(), w, event) }) {
+				return
+			}
+		}
+	}
+}
+
+func (h *OrderWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := loggerFrom(r.Context(), h.logger)
+
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		w.Header().Set("Upgrade", "websocket")
+		WriteProblem(w, r, ErrUpgradeRequired, "")
+		return
+	}
+
+	sub, replay, ok := subscribe(w, r, h.feed, r.URL.Query().Get(paramLastEventID))
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Error("ResponseController.SetWriteDeadline", zap.Error(err))
+	}
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		logger.Error("ResponseController.SetReadDeadline", zap.Error(err))
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		logger.Info("websocket.Accept", zap.Error(err))
+		return
+	}
+	defer conn.CloseNow() //nolint:errcheck
+
+	ctx := conn.CloseRead(r.Context())
+
+	send := func(write func(ctx context.Context) error) bool {
+		writeCtx, cancel := context.WithTimeout(ctx, streamWriteTimeout)
+		defer cancel()
+		if err := write(writeCtx); err != nil {
+			logger.Info("order stream write", zap.Error(err))
+			return false
+		}
+		return true
+	}
+	sendEvent := func(event hub.Event) bool {
+		return send(func(ctx context.Context) error { return writeWSEvent(ctx, conn, event) })
+	}
+
+	for _, event := range replay {
+		if !sendEvent(event) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if !send(conn.Ping) {
+				return
+			}
+		case event, open := <-sub.Events():
+			if !open {
+				logStreamEnd(logger, sub.Err())
+				code := websocket.StatusGoingAway
+				if errors.Is(sub.Err(), hub.ErrSlowSubscriber) {
+					code = websocket.StatusTryAgainLater
+				}
+				if err := conn.Close(code, sub.Err().Error()); err != nil {
+					logger.Info("websocket.Conn.Close", zap.Error(err))
+				}
+				return
+			}
+			if !sendEvent(event) {
+				return
+			}
+		}
+	}
+}
+
+// subscribe writes the problem response on failure.
+func subscribe(w http.ResponseWriter, r *http.Request, feed orderFeed, lastEventID string,
+) (*hub.Subscription, []hub.Event, bool) {
+	query := r.URL.Query()
+	filter := domain.OrderFilter{
+		CustomerID:      query.Get(export.ParamCustomerID),
+		DeliveryService: query.Get(export.ParamDeliveryService),
+	}
+
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		after, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			WriteProblem(w, r, ErrInvalidParameter, "last event id must be a positive integer")
+			return nil, nil, false
+		}
+	}
+
+	sub, replay, err := feed.Subscribe(after, filter.Match)
+	if err != nil {
+		WriteProblem(w, r, ErrShuttingDown, "")
+		return nil, nil, false
+	}
+	return sub, replay, true
+}
+
+func writeSSEEvent(ctx context.Context, w http.ResponseWriter, event hub.Event) error {
+	data, err := json.Marshal(redact.Visible(ctx, event.Order))
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, streamEventOrder, data)
+	return err
+}
+
+func writeWSEvent(ctx context.Context, conn *websocket.Conn, event hub.Event) error {
+	data, err := json.Marshal(OrderStreamMessage{
+		ID:    event.ID,
+		Order: redact.Visible(ctx, event.Order),
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	return conn.Write(ctx, websocket.MessageText, data)
+}
+
+func logStreamEnd(logger logger, err error) {
+	if errors.Is(err, hub.ErrSlowSubscriber) {
+		logger.Info("order stream subscriber dropped", zap.Error(err))
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/hub"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+// closingFeed closes the hub right after subscribing, so the handler sends
+// the replay and returns.
+type closingFeed struct {
+	hub *hub.Hub
+}
+
+func (f closingFeed) Subscribe(after uint64, match func(order *domain.Order) bool,
+) (*hub.Subscription, []hub.Event, error) {
+	sub, replay, err := f.hub.Subscribe(after, match)
+	f.hub.Close()
+	return sub, replay, err
+}
+
+func newTestFeed(t *testing.T, orders []domain.Order) (*hub.Hub, []uint64) {
+	t.Helper()
+	h := hub.New(len(orders), 10)
+	probe, _, err := h.Subscribe(0, func(*domain.Order) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]uint64, 0, len(orders))
+	for i := range orders {
+		h.Publish(&orders[i])
+		ids = append(ids, (<-probe.Events()).ID)
+	}
+	probe.Close()
+	return h, ids
+}
+
+func TestOrderStreamHandlerReplay(t *testing.T) {
+	orders := fixtures.New(7).Orders(3)
+	orders[2].CustomerID = "c-stream"
+	h, ids := newTestFeed(t, orders)
+	handler := NewOrderStreamHandler(closingFeed{h}, "stream", zap.NewNop())
+
+	r := httptest.NewRequest(http.MethodGet, "/orders/stream?customer_id=c-stream", nil)
+	r.Header.Set(headerLastEventID, fmt.Sprint(ids[0]))
+	r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Anonymous))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != mediaTypeEventStream {
+		t.Errorf("Content-Type %q", got)
+	}
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "retry: 3000\n\n") {
+		t.Errorf("body does not start with retry: %q", body)
+	}
+	if want := fmt.Sprintf("id: %d\nevent: order\ndata: {", ids[2]); !strings.Contains(body, want) {
+		t.Errorf("body %q, want the event %d", body, ids[2])
+	}
+	if strings.Count(body, "event: order") != 1 {
+		t.Errorf("body %q, want only the event of the filtered customer", body)
+	}
+	if !strings.Contains(body, orders[2].Delivery.Phone) {
+		t.Errorf("phone masked for a principal allowed to read PII")
+	}
+}
+
+func TestOrderStreamHandlerMasksPII(t *testing.T) {
+	orders := fixtures.New(8).Orders(1)
+	h, ids := newTestFeed(t, orders)
+	handler := NewOrderStreamHandler(closingFeed{h}, "stream", zap.NewNop())
+
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/stream?last_event_id=%d", ids[0]-1), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	if body := rec.Body.String(); !strings.Contains(body, "event: order") ||
+		strings.Contains(body, orders[0].Delivery.Phone) {
+		t.Errorf("body %q, want the order with its phone masked", body)
+	}
+}
+
+func TestOrderStreamHandlerErrors(t *testing.T) {
+	closed := hub.New(1, 1)
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		target  string
+		status  int
+		code    string
+	}{
+		{"invalid last event id", NewOrderStreamHandler(hub.New(1, 1), "stream", zap.NewNop()),
+			"/orders/stream?last_event_id=-1", http.StatusBadRequest, "invalid_parameter"},
+		{"hub closed", NewOrderStreamHandler(closed, "stream", zap.NewNop()),
+			"/orders/stream", http.StatusServiceUnavailable, "shutting_down"},
+		{"websocket without upgrade", NewOrderWebSocketHandler(hub.New(1, 1), "ws", zap.NewNop()),
+			"/orders/ws", http.StatusUpgradeRequired, "upgrade_required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := problemCode(t, rec); got != tt.code {
+				t.Errorf("code %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	exportOrdersUsecase interface {
+		ExportOrders(ctx context.Context, filter domain.OrderFilter, w domain.OrderWriter) (int, error)
+	}
+
+	// streamWriter delays the headers until the first byte.
+	streamWriter struct {
+		w           http.ResponseWriter
+		contentType string
+		filename    string
+		started     bool
+	}
+
+	ExportOrdersHandler struct {
+		name    string
+		usecase exportOrdersUsecase
+		logger  logger
+	}
+)
+
+func NewExportOrdersHandler(usecase exportOrdersUsecase, name string, logger logger) *ExportOrdersHandler {
+	return &ExportOrdersHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *ExportOrdersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := loggerFrom(r.Context(), h.logger)
+	query := r.URL.Query()
+	filter, err := export.ParseFilter(query.Get)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+
+	format := query.Get(export.ParamFormat)
+	if format == "" {
+		format = export.FormatNDJSON
+	}
+	stream := &streamWriter{
+		w:        w,
+		filename: "orders." + format,
+	}
+	writer, err := export.NewWriter(format, stream)
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+	stream.contentType = writer.ContentType()
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Error("ResponseController.SetWriteDeadline", zap.Error(err))
+	}
+
+	count, err := h.usecase.ExportOrders(r.Context(), filter, writer)
+	if err != nil {
+		logger.Error("exportOrdersUsecase.ExportOrders", zap.Error(err), zap.Int("exported", count))
+		if !stream.started {
+			WriteProblem(w, r, ErrInternalServerError, "")
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	logger.Info("orders exported", zap.String("format", format), zap.Int("count", count))
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", s.contentType)
+		s.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.filename))
+		s.w.WriteHeader(http.StatusOK)
+	}
+	return s.w.Write(p)
+}
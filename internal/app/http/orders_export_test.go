@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeExportUsecase struct {
+	orders []domain.Order
+	err    error
+	filter domain.OrderFilter
+}
+
+func (u *fakeExportUsecase) ExportOrders(_ context.Context, filter domain.OrderFilter, w domain.OrderWriter) (int, error) {
+	u.filter = filter
+	for i := range u.orders {
+		if err := w.Write(&u.orders[i]); err != nil {
+			return i, err
+		}
+	}
+	if u.err != nil {
+		return len(u.orders), u.err
+	}
+	return len(u.orders), w.Flush()
+}
+
+func TestExportOrdersHandler(t *testing.T) {
+	usecase := &fakeExportUsecase{orders: fixtures.New(1).Orders(2)}
+	h := NewExportOrdersHandler(usecase, "export", zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/export?format=csv&customer_id=c-1", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("Content-Type %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="orders.csv"` {
+		t.Errorf("Content-Disposition %q", got)
+	}
+	if usecase.filter.CustomerID != "c-1" {
+		t.Errorf("filter %+v", usecase.filter)
+	}
+	if lines := strings.Count(rec.Body.String(), "\n"); lines < 3 {
+		t.Errorf("%d lines, want a header and the orders", lines)
+	}
+}
+
+func TestExportOrdersHandlerDefaultsToNDJSON(t *testing.T) {
+	h := NewExportOrdersHandler(&fakeExportUsecase{orders: fixtures.New(1).Orders(2)}, "export", zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/export", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type %q", got)
+	}
+	if lines := strings.Count(rec.Body.String(), "\n"); lines != 2 {
+		t.Errorf("%d lines, want 2", lines)
+	}
+}
+
+func TestExportOrdersHandlerInvalidParameters(t *testing.T) {
+	h := NewExportOrdersHandler(&fakeExportUsecase{}, "export", zap.NewNop())
+
+	for _, query := range []string{"format=xml", "date_from=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/export?"+query, nil))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want 400", rec.Code)
+			}
+			if code := problemCode(t, rec); code != "invalid_parameter" {
+				t.Errorf("code %q", code)
+			}
+		})
+	}
+}
+
+func TestExportOrdersHandlerErrorBeforeStream(t *testing.T) {
+	h := NewExportOrdersHandler(&fakeExportUsecase{err: errors.New("db down")}, "export", zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/export", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d, want 500", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != "" {
+		t.Errorf("Content-Disposition %q on an error", got)
+	}
+}
+
+func TestExportOrdersHandlerErrorMidStream(t *testing.T) {
+	usecase := &fakeExportUsecase{orders: fixtures.New(1).Orders(1), err: errors.New("db down")}
+	h := NewExportOrdersHandler(usecase, "export", zap.NewNop())
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/export", nil))
+	t.Fatal("handler returned after a mid-stream error")
+}
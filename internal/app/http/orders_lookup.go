@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const maxLookupOrders = 100
+
+type (
+	lookupOrdersUsecase interface {
+		GetOrdersByKey(ctx context.Context, key domain.OrderKey, limit int) ([]*domain.Order, error)
+	}
+
+	LookupOrdersResponse struct {
+		Orders []*domain.Order `json:"orders"`
+	}
+
+	LookupOrdersHandler struct {
+		name    string
+		kind    domain.OrderKeyKind
+		param   string
+		usecase lookupOrdersUsecase
+		logger  logger
+	}
+)
+
+func NewLookupOrdersHandler(usecase lookupOrdersUsecase, kind domain.OrderKeyKind, param, name string,
+	logger logger,
+) *LookupOrdersHandler {
+	return &LookupOrdersHandler{
+		name:    name,
+		kind:    kind,
+		param:   param,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *LookupOrdersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := domain.OrderKey{Kind: h.kind, Value: r.PathValue(h.param)}
+	if !key.Valid() {
+		WriteProblem(w, r, ErrInvalidParameter, fmt.Sprintf("invalid %s", h.param))
+		return
+	}
+
+	ctx := r.Context()
+	logger := loggerFrom(ctx, h.logger)
+	orders, err := h.usecase.GetOrdersByKey(ctx, key, maxLookupOrders)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			logger.Info("order not found", zap.String("key", string(key.Kind)))
+			WriteProblem(w, r, err, "")
+			return
+		}
+		logger.Error("lookupUsecase.GetOrdersByKey", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	visible := make([]*domain.Order, 0, len(orders))
+	for _, order := range orders {
+		visible = append(visible, redact.Visible(ctx, order))
+	}
+	response, err := json.Marshal(LookupOrdersResponse{Orders: visible})
+	if err != nil {
+		logger.Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	GetSuccessResponseWithBody(w, response)
+}
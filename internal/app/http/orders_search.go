@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/redact"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	paramSearchText   = "q"
+	paramSearchLimit  = "limit"
+	paramSearchOffset = "offset"
+
+	maxSearchText      = 200
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchOffset    = 1000
+)
+
+type (
+	searchOrdersUsecase interface {
+		SearchOrders(ctx context.Context, search domain.OrderSearch) ([]domain.OrderHit, error)
+	}
+
+	SearchOrdersResponse struct {
+		Results []SearchOrdersResult `json:"results"`
+	}
+	// SearchOrdersResult has the matches marked with <mark> in HTML-escaped values.
+	SearchOrdersResult struct {
+		Order      *domain.Order     `json:"order"`
+		Rank       float64           `json:"rank"`
+		Highlights map[string]string `json:"highlights"`
+	}
+
+	SearchOrdersHandler struct {
+		name    string
+		usecase searchOrdersUsecase
+		logger  logger
+	}
+)
+
+func NewSearchOrdersHandler(usecase searchOrdersUsecase, name string, logger logger) *SearchOrdersHandler {
+	return &SearchOrdersHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *SearchOrdersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	search, err := parseSearch(r.URL.Query())
+	if err != nil {
+		WriteProblem(w, r, ErrInvalidParameter, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	search.PII = redact.PIIAllowed(ctx)
+	hits, err := h.usecase.SearchOrders(ctx, search)
+	if err != nil {
+		loggerFrom(ctx, h.logger).Error("searchOrdersUsecase.SearchOrders", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	results := make([]SearchOrdersResult, 0, len(hits))
+	for _, hit := range hits {
+		visible := redact.Visible(ctx, hit.Order)
+		results = append(results, SearchOrdersResult{
+			Order:      visible,
+			Rank:       hit.Rank,
+			Highlights: highlights(hit.Order, visible, search),
+		})
+	}
+
+	response, err := json.Marshal(SearchOrdersResponse{Results: results})
+	if err != nil {
+		loggerFrom(ctx, h.logger).Error("json.Marshal", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+	GetSuccessResponseWithBody(w, response)
+}
+
+func parseSearch(query url.Values) (domain.OrderSearch, error) {
+	search := domain.OrderSearch{
+		Text: strings.TrimSpace(query.Get(paramSearchText)),
+	}
+	if search.Text == "" || utf8.RuneCountInString(search.Text) > maxSearchText {
+		return domain.OrderSearch{}, fmt.Errorf("%s must be 1 to %d characters", paramSearchText, maxSearchText)
+	}
+
+	var err error
+	if search.Limit, err = intParam(query, paramSearchLimit, defaultSearchLimit, 1, maxSearchLimit); err != nil {
+		return domain.OrderSearch{}, err
+	}
+	if search.Offset, err = intParam(query, paramSearchOffset, 0, 0, maxSearchOffset); err != nil {
+		return domain.OrderSearch{}, err
+	}
+	return search, nil
+}
+
+func intParam(query url.Values, name string, def, minimum, maximum int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minimum || n > maximum {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, minimum, maximum)
+	}
+	return n, nil
+}
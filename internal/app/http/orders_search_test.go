@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type fakeSearchUsecase struct {
+	search domain.OrderSearch
+	hits   []domain.OrderHit
+	err    error
+}
+
+func (u *fakeSearchUsecase) SearchOrders(_ context.Context, search domain.OrderSearch) ([]domain.OrderHit, error) {
+	u.search = search
+	return u.hits, u.err
+}
+
+func searchOrders(h http.Handler, target string, principal *auth.Principal) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if principal != nil {
+		r = r.WithContext(auth.WithPrincipal(r.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestSearchOrdersHandler(t *testing.T) {
+	order := testSearchOrder()
+	reader := auth.Principal{Subject: "support", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		pii       bool
+		highlight []string
+	}{
+		{"with PII", &auth.Anonymous, true, []string{"delivery.address", "delivery.city", "delivery.email",
+			"delivery.name", "items[0].brand"}},
+		{"without PII", &reader, false, []string{"delivery.city", "items[0].brand"}},
+		{"unauthenticated", nil, false, []string{"delivery.city", "items[0].brand"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &fakeSearchUsecase{hits: []domain.OrderHit{{Order: order, Rank: 0.5}}}
+			h := NewSearchOrdersHandler(u, "searchOrders", zap.NewNop())
+
+			rec := searchOrders(h, "/orders/search?q=+moscow+&limit=5&offset=10", tt.principal)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+			want := domain.OrderSearch{Text: "moscow", Limit: 5, Offset: 10, PII: tt.pii}
+			if u.search != want {
+				t.Errorf("search %+v, want %+v", u.search, want)
+			}
+
+			var response SearchOrdersResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Results) != 1 {
+				t.Fatalf("results %+v", response.Results)
+			}
+			result := response.Results[0]
+			if result.Rank != 0.5 {
+				t.Errorf("rank %v", result.Rank)
+			}
+			if masked := result.Order.Delivery.Name != order.Delivery.Name; masked == tt.pii {
+				t.Errorf("name %q with PII %v", result.Order.Delivery.Name, tt.pii)
+			}
+			for _, path := range tt.highlight {
+				if _, ok := result.Highlights[path]; !ok {
+					t.Errorf("highlights %v without %s", result.Highlights, path)
+				}
+			}
+			if len(result.Highlights) != len(tt.highlight) {
+				t.Errorf("highlights %v, want only %v", result.Highlights, tt.highlight)
+			}
+		})
+	}
+}
+
+func TestSearchOrdersHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name, target string
+		err          error
+		status       int
+		code         string
+	}{
+		{"no query", "/orders/search", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"blank query", "/orders/search?q=++", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"query too long", "/orders/search?q=" + strings.Repeat("a", maxSearchText+1), nil,
+			http.StatusBadRequest, "invalid_parameter"},
+		{"limit", "/orders/search?q=a&limit=101", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"offset", "/orders/search?q=a&offset=1001", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"usecase", "/orders/search?q=a", errors.New("db down"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSearchOrdersHandler(&fakeSearchUsecase{err: tt.err}, "searchOrders", zap.NewNop())
+
+			rec := searchOrders(h, tt.target, nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := problemCode(t, rec); got != tt.code {
+				t.Errorf("code %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/report"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+const (
+	mediaTypeProblem  = "application/problem+json"
+	problemTypePrefix = "urn:wbtech-l0:problem:"
+)
+
+type (
+	// Problem is an RFC 7807 body; only Code is stable.
+	Problem struct {
+		Type       string             `json:"type"`
+		Title      string             `json:"title"`
+		Status     int                `json:"status"`
+		Detail     string             `json:"detail,omitempty"`
+		Instance   string             `json:"instance,omitempty"`
+		Code       string             `json:"code"`
+		RequestID  string             `json:"request_id,omitempty"`
+		Violations []ingest.Violation `json:"violations,omitempty"`
+	}
+
+	ProblemError struct {
+		status int
+		code   string
+		title  string
+	}
+)
+
+var domainProblems = []struct {
+	err     error
+	problem *ProblemError
+}{
+	{domain.ErrOrderNotFound, NewProblemError(http.StatusNotFound, "order_not_found", "order not found")},
+	{domain.ErrOrderAlreadyExists, NewProblemError(http.StatusConflict, "order_already_exists", "order already exists")},
+	{domain.ErrCustomerNotFound, NewProblemError(http.StatusNotFound, "customer_not_found", "customer not found")},
+	{ingest.ErrDecode, ErrInvalidBody},
+	{export.ErrInvalidFilter, ErrInvalidParameter},
+	{export.ErrUnknownFormat, ErrInvalidParameter},
+	{domain.ErrUnknownReport, NewProblemError(http.StatusNotFound, "report_not_found", "report not found")},
+	{report.ErrInvalidQuery, ErrInvalidParameter},
+	{report.ErrUnknownFormat, ErrInvalidParameter},
+}
+
+func NewProblemError(status int, code, title string) *ProblemError {
+	return &ProblemError{
+		status: status,
+		code:   code,
+		title:  title,
+	}
+}
+
+func (e *ProblemError) Error() string {
+	return e.title
+}
+
+func (e *ProblemError) Status() int {
+	return e.status
+}
+
+func (e *ProblemError) Code() string {
+	return e.code
+}
+
+func NewProblem(r *http.Request, err error, detail string) Problem {
+	p := problemFor(err)
+	problem := Problem{
+		Type:      problemTypePrefix + p.code,
+		Title:     p.title,
+		Status:    p.status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      p.code,
+		RequestID: logctx.RequestID(r.Context()),
+	}
+
+	var validationErr *ingest.ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Violations = validationErr.Violations
+	}
+
+	return problem
+}
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	problem := NewProblem(r, err, detail)
+	w.Header().Set("Content-Type", mediaTypeProblem)
+	w.WriteHeader(problem.Status)
+	if errEnc := json.NewEncoder(w).Encode(problem); errEnc != nil {
+		log.Printf("http.WriteProblem: %v\n", errEnc)
+	}
+}
+
+func problemFor(err error) *ProblemError {
+	var problemErr *ProblemError
+	if errors.As(err, &problemErr) {
+		return problemErr
+	}
+
+	var validationErr *ingest.ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrValidationFailed
+	}
+
+	for _, mapped := range domainProblems {
+		if errors.Is(err, mapped.err) {
+			return mapped.problem
+		}
+	}
+
+	return ErrInternalServerError
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"problem error", ErrInvalidParameter, http.StatusBadRequest, "invalid_parameter"},
+		{"wrapped domain error", fmt.Errorf("repo: %w", domain.ErrOrderNotFound), http.StatusNotFound, "order_not_found"},
+		{"conflict", domain.ErrOrderAlreadyExists, http.StatusConflict, "order_already_exists"},
+		{"decode error", fmt.Errorf("%w: eof", ingest.ErrDecode), http.StatusBadRequest, "invalid_body"},
+		{"unknown error", errors.New("db down"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/order/x", nil)
+			r = r.WithContext(logctx.With(r.Context(), zap.NewNop(), "req-1"))
+			rec := httptest.NewRecorder()
+			WriteProblem(rec, r, tt.err, "some detail")
+
+			if rec.Code != tt.status || rec.Header().Get("Content-Type") != mediaTypeProblem {
+				t.Fatalf("status %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			want := Problem{
+				Type: problemTypePrefix + tt.code, Title: problem.Title, Status: tt.status, Detail: "some detail",
+				Instance: "/order/x", Code: tt.code, RequestID: "req-1",
+			}
+			if !reflect.DeepEqual(problem, want) || problem.Title == "" {
+				t.Errorf("problem %+v, want %+v", problem, want)
+			}
+		})
+	}
+}
+
+func TestWriteProblemViolations(t *testing.T) {
+	violations := []ingest.Violation{{Field: "delivery.email", Rule: "email"}}
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, httptest.NewRequest(http.MethodPost, "/orders", nil),
+		fmt.Errorf("decode: %w", &ingest.ValidationError{Violations: violations}), "")
+
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || problem.Code != "validation_failed" ||
+		len(problem.Violations) != 1 || problem.Violations[0] != violations[0] {
+		t.Errorf("status %d, problem %+v", rec.Code, problem)
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	templatesPattern  = "*.html.tmpl"
+	orderTemplateName = "order"
+)
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+type (
+	orderPage struct {
+		Order      *domain.Order
+		ItemsTotal int
+		Status     int
+		Title      string
+		Message    string
+		Format     LocaleFormatter
+	}
+
+	HTMLRenderer struct {
+		tmpl *template.Template
+	}
+)
+
+// NewHTMLRenderer parses the embedded templates, then the ones from dir,
+// whose blocks override the embedded ones.
+func NewHTMLRenderer(dir string) (*HTMLRenderer, error) {
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("fs.Sub: %w", err)
+	}
+
+	tmpl, err := template.New("").ParseFS(embedded, templatesPattern)
+	if err != nil {
+		return nil, fmt.Errorf("template.ParseFS: %w", err)
+	}
+
+	if dir != "" {
+		overrides := os.DirFS(dir)
+		matches, err := fs.Glob(overrides, templatesPattern)
+		if err != nil {
+			return nil, fmt.Errorf("fs.Glob: %w", err)
+		}
+		if len(matches) > 0 {
+			if tmpl, err = tmpl.ParseFS(overrides, templatesPattern); err != nil {
+				return nil, fmt.Errorf("template.ParseFS %q: %w", dir, err)
+			}
+		}
+	}
+
+	return &HTMLRenderer{
+		tmpl: tmpl,
+	}, nil
+}
+
+func (r *HTMLRenderer) RenderOrder(w http.ResponseWriter, order *domain.Order) error {
+	itemsTotal := 0
+	for _, item := range order.Items {
+		itemsTotal += item.TotalPrice
+	}
+
+	return r.render(w, http.StatusOK, orderPage{
+		Order:      order,
+		ItemsTotal: itemsTotal,
+		Format:     NewLocaleFormatter(order.Locale),
+	})
+}
+
+func (r *HTMLRenderer) RenderError(w http.ResponseWriter, status int, err error) error {
+	return r.render(w, status, orderPage{
+		Status:  status,
+		Title:   http.StatusText(status),
+		Message: err.Error(),
+		Format:  NewLocaleFormatter(""),
+	})
+}
+
+func (r *HTMLRenderer) render(w http.ResponseWriter, status int, page orderPage) error {
+	// A template error must not leave a half-written 200 page.
+	buf := &bytes.Buffer{}
+	if err := r.tmpl.ExecuteTemplate(buf, orderTemplateName, page); err != nil {
+		return fmt.Errorf("tmpl.ExecuteTemplate: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("http.HTMLRenderer.render: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func TestHTMLRendererRenderOrder(t *testing.T) {
+	renderer, err := NewHTMLRenderer("")
+	if err != nil {
+		t.Fatalf("NewHTMLRenderer: %v", err)
+	}
+	order := fixtures.New(1).Order()
+	order.Locale = "ru"
+	order.Delivery.Name = "<script>alert(1)</script>"
+
+	rec := httptest.NewRecorder()
+	if err := renderer.RenderOrder(rec, &order); err != nil {
+		t.Fatalf("RenderOrder: %v", err)
+	}
+
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK {
+		t.Errorf("status %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type %q", got)
+	}
+	for _, want := range []string{`<html lang="ru">`, order.OrderUID, order.Items[0].Name, "&lt;script&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+	if strings.Contains(body, "<script>") {
+		t.Error("delivery name is not escaped")
+	}
+}
+
+func TestHTMLRendererRenderError(t *testing.T) {
+	renderer, err := NewHTMLRenderer("")
+	if err != nil {
+		t.Fatalf("NewHTMLRenderer: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := renderer.RenderError(rec, http.StatusNotFound, errors.New("order not found")); err != nil {
+		t.Fatalf("RenderError: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status %d, want 404", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "order not found") {
+		t.Error("page does not contain the error")
+	}
+}
+
+func TestHTMLRendererOverrides(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "brand.html.tmpl"), []byte(`{{define "brand"}}My Shop{{end}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := NewHTMLRenderer(dir)
+	if err != nil {
+		t.Fatalf("NewHTMLRenderer: %v", err)
+	}
+
+	order := fixtures.New(1).Order()
+	rec := httptest.NewRecorder()
+	if err := renderer.RenderOrder(rec, &order); err != nil {
+		t.Fatalf("RenderOrder: %v", err)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "My Shop") || strings.Contains(body, "Service L0") {
+		t.Error("brand is not overridden")
+	}
+}
+
+func TestNewHTMLRendererInvalidOverride(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "broken.html.tmpl"), []byte(`{{define "brand"}}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHTMLRenderer(dir); err == nil {
+		t.Fatal("expected a parse error")
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/report"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	reportUsecase interface {
+		Report(ctx context.Context, kind domain.ReportKind, query domain.ReportQuery) (*domain.Report, error)
+	}
+
+	ReportHandler struct {
+		name    string
+		usecase reportUsecase
+		logger  logger
+	}
+)
+
+func NewReportHandler(usecase reportUsecase, name string, logger logger) *ReportHandler {
+	return &ReportHandler{
+		name:    name,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (h *ReportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kind, err := report.ParseKind(r.PathValue(definitions.ParamReport))
+	if err != nil {
+		WriteProblem(w, r, err, "")
+		return
+	}
+	params := r.URL.Query()
+	query, err := report.ParseQuery(params.Get)
+	if err != nil {
+		WriteProblem(w, r, err, err.Error())
+		return
+	}
+	encoder, err := report.NewEncoder(params.Get(report.ParamFormat))
+	if err != nil {
+		WriteProblem(w, r, err, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	logger := loggerFrom(ctx, h.logger)
+	result, err := h.usecase.Report(ctx, kind, query)
+	if err != nil {
+		logger.Error("reportUsecase.Report", zap.Error(err), zap.String("report", string(kind)))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	body := bytes.Buffer{}
+	if err := encoder.Encode(&body, kind, query, result); err != nil {
+		logger.Error("report.Encoder.Encode", zap.Error(err))
+		WriteProblem(w, r, ErrInternalServerError, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", encoder.ContentType())
+	if params.Get(report.ParamFormat) == report.FormatCSV {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", string(kind)+".csv"))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		logger.Error("http.ResponseWriter.Write", zap.Error(err))
+	}
+}
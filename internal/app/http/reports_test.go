@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type fakeReportUsecase struct {
+	kind  domain.ReportKind
+	query domain.ReportQuery
+	err   error
+}
+
+func (u *fakeReportUsecase) Report(_ context.Context, kind domain.ReportKind, query domain.ReportQuery,
+) (*domain.Report, error) {
+	u.kind, u.query = kind, query
+	if u.err != nil {
+		return nil, u.err
+	}
+	return &domain.Report{Columns: []string{"currency", "orders"}, Rows: [][]any{{"RUB", int64(2)}}}, nil
+}
+
+func getReport(h http.Handler, name, rawQuery string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/reports/"+name+"?"+rawQuery, nil)
+	r.SetPathValue(definitions.ParamReport, name)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestReportHandler(t *testing.T) {
+	u := &fakeReportUsecase{}
+	h := NewReportHandler(u, "report", zap.NewNop())
+
+	rec := getReport(h, "currencies", "tz=Europe/Moscow&limit=3")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if u.kind != domain.ReportCurrencies || u.query.Limit != 3 || u.query.Location.String() != "Europe/Moscow" {
+		t.Errorf("kind %q, query %+v", u.kind, u.query)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type %q", got)
+	}
+	if want := `"rows":[{"currency":"RUB","orders":2}]`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("body %s, want %s", rec.Body, want)
+	}
+
+	rec = getReport(h, "currencies", "format=csv")
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="currencies.csv"` {
+		t.Errorf("Content-Disposition %q", got)
+	}
+	if got := rec.Body.String(); got != "currency,orders\nRUB,2\n" {
+		t.Errorf("body %q", got)
+	}
+}
+
+func TestReportHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name, report, query string
+		err                 error
+		status              int
+		code                string
+	}{
+		{"unknown report", "weekly", "", nil, http.StatusNotFound, "report_not_found"},
+		{"unknown time zone", "daily", "tz=Mars/Olympus", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"invalid date", "daily", "date_from=yesterday", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"unknown format", "daily", "format=xml", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"usecase", "daily", "", errors.New("db down"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewReportHandler(&fakeReportUsecase{err: tt.err}, "report", zap.NewNop())
+
+			rec := getReport(h, tt.report, tt.query)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := problemCode(t, rec); got != tt.code {
+				t.Errorf("code %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
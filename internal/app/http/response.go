@@ -1,33 +1,29 @@
 package http
 
 import (
-	"encoding/json"
+	"context"
 	"log"
 	"net/http"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
 )
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+func GetSuccessResponseWithBody(w http.ResponseWriter, body []byte) {
+	GetResponseWithBody(w, http.StatusOK, body)
 }
 
-func GetSuccessResponseWithBody(w http.ResponseWriter, body []byte) {
+func GetResponseWithBody(w http.ResponseWriter, status int, body []byte) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	_, err := w.Write(body)
 	if err != nil {
-		log.Printf("http.GetSuccessResponseWithBody: %v\n", err)
+		log.Printf("http.GetResponseWithBody: %v\n", err)
 	}
 }
 
-func GetErrorResponse(w http.ResponseWriter, status int, err error, details string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	errEnc := json.NewEncoder(w).Encode(ErrorResponse{
-		Error:   err.Error(),
-		Details: details,
-	})
-	if errEnc != nil {
-		log.Printf("http.GetErrorResponse: %v\n", err)
+func loggerFrom(ctx context.Context, fallback logger) logger {
+	if l, ok := logctx.Logger(ctx); ok {
+		return l
 	}
+	return fallback
 }
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	staticIndexFile   = "index.html"
+	staticIndexCache  = "no-cache"
+	staticAssetsCache = "public, max-age=3600"
+	staticDefaultMIME = "application/octet-stream"
+	etagHashBytes     = 16
+)
+
+type (
+	staticFile struct {
+		data        []byte
+		etag        string
+		contentType string
+	}
+
+	StaticHandler struct {
+		files   map[string]staticFile
+		modTime time.Time
+	}
+)
+
+// NewStaticHandler keeps every file of fsys in memory with a strong ETag.
+func NewStaticHandler(fsys fs.FS) (*StaticHandler, error) {
+	files := make(map[string]staticFile)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = staticDefaultMIME
+		}
+
+		files[name] = staticFile{
+			data:        data,
+			etag:        strongETag(data),
+			contentType: contentType,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fs.WalkDir: %w", err)
+	}
+
+	if _, inMap := files[staticIndexFile]; !inMap {
+		return nil, fmt.Errorf("static: %s not found", staticIndexFile)
+	}
+
+	return &StaticHandler{
+		files:   files,
+		modTime: time.Now(),
+	}, nil
+}
+
+func (h *StaticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		WriteProblem(w, r, ErrMethodNotAllowed, "")
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	file, inMap := h.files[name]
+	if !inMap {
+		// Unknown paths fall back to the UI entry point.
+		name = staticIndexFile
+		file = h.files[name]
+	}
+
+	cacheControl := staticAssetsCache
+	if name == staticIndexFile {
+		cacheControl = staticIndexCache
+	}
+
+	w.Header().Set("Content-Type", file.contentType)
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", file.etag)
+	http.ServeContent(w, r, name, h.modTime, bytes.NewReader(file.data))
+}
+
+func strongETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:etagHashBytes]) + `"`
+}
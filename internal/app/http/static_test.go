@@ -0,0 +1,97 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestStaticHandler(t *testing.T) *StaticHandler {
+	t.Helper()
+	h, err := NewStaticHandler(fstest.MapFS{
+		"index.html":    {Data: []byte("<html>index</html>")},
+		"js/app.js":     {Data: []byte("console.log(1)")},
+		"css/style.css": {Data: []byte("body{}")},
+	})
+	if err != nil {
+		t.Fatalf("NewStaticHandler: %v", err)
+	}
+	return h
+}
+
+func TestNewStaticHandlerRequiresIndex(t *testing.T) {
+	_, err := NewStaticHandler(fstest.MapFS{"app.js": {Data: []byte("x")}})
+	if err == nil {
+		t.Fatal("expected an error without index.html")
+	}
+}
+
+func TestStaticHandlerServesAssets(t *testing.T) {
+	h := newTestStaticHandler(t)
+
+	tests := []struct {
+		path, body, contentType, cacheControl string
+	}{
+		{"/js/app.js", "console.log(1)", "text/javascript", staticAssetsCache},
+		{"/css/style.css", "body{}", "text/css", staticAssetsCache},
+		{"/", "<html>index</html>", "text/html", staticIndexCache},
+		{"/orders/unknown", "<html>index</html>", "text/html", staticIndexCache},
+		{"/../index.html", "<html>index</html>", "text/html", staticIndexCache},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want 200", rec.Code)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("body %q, want %q", got, tt.body)
+			}
+			if got, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type")); got != tt.contentType {
+				t.Errorf("Content-Type %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control %q, want %q", got, tt.cacheControl)
+			}
+		})
+	}
+}
+
+func TestStaticHandlerConditionalGet(t *testing.T) {
+	h := newTestStaticHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/js/app.js", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/js/app.js", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("status %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("304 with a body: %q", rec.Body.String())
+	}
+}
+
+func TestStaticHandlerRejectsWrites(t *testing.T) {
+	h := newTestStaticHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/index.html", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status %d, want 405", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Errorf("Allow %q", got)
+	}
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const maxLineBytes = 16 << 20
+
+type (
+	repository interface {
+		AddOrders(ctx context.Context, orders []domain.Order) ([]string, error)
+	}
+	logger interface {
+		Info(msg string, fields ...zap.Field)
+		Error(msg string, fields ...zap.Field)
+	}
+
+	Rejection struct {
+		Line     int    `json:"line"`
+		OrderUID string `json:"order_uid,omitempty"`
+		Reason   string `json:"reason"`
+	}
+	Report struct {
+		Accepted      int         `json:"accepted"`
+		Rejected      int         `json:"rejected"`
+		Duplicates    int         `json:"duplicates"`
+		Rejections    []Rejection `json:"rejections"`
+		DuplicateUIDs []string    `json:"duplicate_uids"`
+	}
+
+	// Checkpoint receives the last fully processed line.
+	Checkpoint func(line int) error
+
+	Importer struct {
+		decoder   *ingest.Decoder
+		repo      repository
+		batchSize int
+		logger    logger
+	}
+)
+
+func New(decoder *ingest.Decoder, repo repository, batchSize int, logger logger) *Importer {
+	return &Importer{
+		decoder:   decoder,
+		repo:      repo,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+func (i *Importer) Import(ctx context.Context, r io.Reader, skipLines int, checkpoint Checkpoint) (Report, error) {
+	report := Report{
+		Rejections:    []Rejection{},
+		DuplicateUIDs: []string{},
+	}
+	batch := make([]domain.Order, 0, i.batchSize)
+	lines := make([]int, 0, i.batchSize)
+
+	flush := func(line int) error {
+		if len(batch) > 0 {
+			if err := i.store(ctx, batch, lines, &report); err != nil {
+				return err
+			}
+			batch = batch[:0]
+			lines = lines[:0]
+		}
+		return checkpoint(line)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line <= skipLines {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		order, err := i.decoder.Decode(data)
+		if err != nil {
+			report.reject(line, peekOrderUID(data), err)
+			continue
+		}
+		batch = append(batch, order)
+		lines = append(lines, line)
+
+		if len(batch) >= i.batchSize {
+			if err := flush(line); err != nil {
+				return report, err
+			}
+			i.logger.Info("import progress", zap.Int("line", line), zap.Int("accepted", report.Accepted),
+				zap.Int("rejected", report.Rejected), zap.Int("duplicates", report.Duplicates))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("line %d: %w", line+1, err)
+	}
+
+	if err := flush(line); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// store retries a failed batch one order at a time and rejects the orders that still fail.
+func (i *Importer) store(ctx context.Context, batch []domain.Order, lines []int, report *Report) error {
+	duplicates, err := i.repo.AddOrders(ctx, batch)
+	if err == nil {
+		report.accept(len(batch), duplicates)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("repo.AddOrders: %w", err)
+	}
+	if len(batch) > 1 {
+		i.logger.Error("repo.AddOrders", zap.Error(err), zap.Int("line", lines[0]), zap.Int("orders", len(batch)))
+	}
+
+	for n := range batch {
+		if len(batch) > 1 {
+			duplicates, err = i.repo.AddOrders(ctx, batch[n:n+1])
+		}
+		if err == nil {
+			report.accept(1, duplicates)
+			continue
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("repo.AddOrders: %w", err)
+		}
+		report.reject(lines[n], batch[n].OrderUID, err)
+	}
+	return nil
+}
+
+func (r *Report) accept(stored int, duplicates []string) {
+	r.Duplicates += len(duplicates)
+	r.DuplicateUIDs = append(r.DuplicateUIDs, duplicates...)
+	r.Accepted += stored - len(duplicates)
+}
+
+func (r *Report) reject(line int, orderUID string, err error) {
+	r.Rejected++
+	r.Rejections = append(r.Rejections, Rejection{
+		Line:     line,
+		OrderUID: orderUID,
+		Reason:   err.Error(),
+	})
+}
+
+func peekOrderUID(data []byte) string {
+	var order struct {
+		OrderUID string `json:"order_uid"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return ""
+	}
+	return order.OrderUID
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+type fakeRepo struct {
+	stored  map[string]bool
+	failing map[string]bool
+	batches []int
+}
+
+var errInsert = errors.New("insert failed")
+
+func newFakeRepo(failing ...string) *fakeRepo {
+	repo := &fakeRepo{stored: map[string]bool{}, failing: map[string]bool{}}
+	for _, uid := range failing {
+		repo.failing[uid] = true
+	}
+	return repo
+}
+
+func (r *fakeRepo) AddOrders(_ context.Context, orders []domain.Order) ([]string, error) {
+	r.batches = append(r.batches, len(orders))
+	for _, order := range orders {
+		if r.failing[order.OrderUID] {
+			return nil, errInsert
+		}
+	}
+	duplicates := []string{}
+	for _, order := range orders {
+		if r.stored[order.OrderUID] {
+			duplicates = append(duplicates, order.OrderUID)
+		}
+		r.stored[order.OrderUID] = true
+	}
+	return duplicates, nil
+}
+
+func ndjson(t *testing.T, orders []domain.Order) string {
+	t.Helper()
+	var b strings.Builder
+	for _, order := range orders {
+		data, err := json.Marshal(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestImport(t *testing.T) {
+	orders := fixtures.New(1).Orders(5)
+	invalid := orders[1]
+	invalid.Delivery.Email = "not an email"
+	input := ndjson(t, []domain.Order{orders[0], invalid, orders[2], orders[0]}) + "\n{broken\n" +
+		ndjson(t, orders[3:])
+
+	repo := newFakeRepo()
+	var checkpoints []int
+	report, err := New(ingest.NewDecoder(), repo, 2, zap.NewNop()).Import(context.Background(),
+		strings.NewReader(input), 0, func(line int) error {
+			checkpoints = append(checkpoints, line)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	if report.Accepted != 4 || report.Duplicates != 1 || report.Rejected != 2 {
+		t.Errorf("report %+v", report)
+	}
+	if got := report.DuplicateUIDs; !slices.Equal(got, []string{orders[0].OrderUID}) {
+		t.Errorf("duplicates %v", got)
+	}
+	if got := report.Rejections; len(got) != 2 || got[0].Line != 2 || got[0].OrderUID != invalid.OrderUID ||
+		got[1].Line != 6 || got[1].OrderUID != "" {
+		t.Errorf("rejections %+v", got)
+	}
+	if !slices.Equal(checkpoints, []int{3, 7, 8}) {
+		t.Errorf("checkpoints %v", checkpoints)
+	}
+}
+
+func TestImportRetriesFailedBatchOneByOne(t *testing.T) {
+	orders := fixtures.New(1).Orders(3)
+	repo := newFakeRepo(orders[1].OrderUID)
+
+	report, err := New(ingest.NewDecoder(), repo, 3, zap.NewNop()).Import(context.Background(),
+		strings.NewReader(ndjson(t, orders)), 0, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	if report.Accepted != 2 || report.Rejected != 1 {
+		t.Errorf("report %+v", report)
+	}
+	want := Rejection{Line: 2, OrderUID: orders[1].OrderUID, Reason: errInsert.Error()}
+	if len(report.Rejections) != 1 || report.Rejections[0] != want {
+		t.Errorf("rejections %+v, want %+v", report.Rejections, want)
+	}
+	if !slices.Equal(repo.batches, []int{3, 1, 1, 1}) {
+		t.Errorf("batches %v", repo.batches)
+	}
+	if !repo.stored[orders[0].OrderUID] || !repo.stored[orders[2].OrderUID] {
+		t.Errorf("stored %v", repo.stored)
+	}
+}
+
+func TestImportResumes(t *testing.T) {
+	orders := fixtures.New(1).Orders(4)
+	repo := newFakeRepo()
+
+	report, err := New(ingest.NewDecoder(), repo, 10, zap.NewNop()).Import(context.Background(),
+		strings.NewReader(ndjson(t, orders)), 3, func(int) error { return nil })
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if report.Accepted != 1 || !repo.stored[orders[3].OrderUID] {
+		t.Errorf("report %+v, stored %v", report, repo.stored)
+	}
+}
+
+func TestImportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(ingest.NewDecoder(), newFakeRepo(), 10, zap.NewNop()).Import(ctx,
+		strings.NewReader(ndjson(t, fixtures.New(1).Orders(2))), 0, func(int) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,109 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	// Violation describes a single failed validation rule of an order field.
+	Violation struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+		Param string `json:"param,omitempty"`
+	}
+
+	// ValidationError is returned by Decoder when the order is well-formed JSON
+	// but breaks domain.Order validation rules.
+	ValidationError struct {
+		Violations []Violation
+	}
+
+	// Decoder turns raw order payloads from Kafka or HTTP into validated orders.
+	Decoder struct {
+		validate *validator.Validate
+	}
+)
+
+var ErrDecode = errors.New("invalid order json")
+
+func NewDecoder() *Decoder {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	return &Decoder{
+		validate: validate,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		fields = append(fields, v.Field+":"+v.Rule)
+	}
+	return "order validation failed: " + strings.Join(fields, ", ")
+}
+
+func (d *Decoder) Decode(data []byte) (domain.Order, error) {
+	order := domain.Order{}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return domain.Order{}, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	if err := d.Validate(order); err != nil {
+		return domain.Order{}, err
+	}
+
+	return order, nil
+}
+
+func (d *Decoder) Validate(order domain.Order) error {
+	err := d.validate.Struct(order)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	violations := make([]Violation, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		violations = append(violations, Violation{
+			Field: fieldPath(fe.Namespace()),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+		})
+	}
+
+	return &ValidationError{
+		Violations: violations,
+	}
+}
+
+// fieldPath drops the root struct name: "Order.delivery.email" -> "delivery.email".
+func fieldPath(namespace string) string {
+	if _, path, found := strings.Cut(namespace, "."); found {
+		return path
+	}
+	return namespace
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func TestDecoderDecode(t *testing.T) {
+	want := fixtures.New(1).Order()
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewDecoder().Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.OrderUID != want.OrderUID || len(got.Items) != len(want.Items) ||
+		got.Payment.Amount != want.Payment.Amount || !got.DateCreated.Equal(want.DateCreated) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestDecoderDecodeMalformed(t *testing.T) {
+	_, err := NewDecoder().Decode([]byte(`{"order_uid": 1}`))
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf("error %v, want ErrDecode", err)
+	}
+}
+
+func TestDecoderViolations(t *testing.T) {
+	order := fixtures.New(1).Order()
+	order.OrderUID = "short"
+	order.Delivery.Email = "not an email"
+	order.Items[0].ChrtID = 0
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewDecoder().Decode(data)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error %v, want a ValidationError", err)
+	}
+
+	var fields []string
+	for _, v := range validationErr.Violations {
+		fields = append(fields, v.Field+":"+v.Rule)
+	}
+	for _, want := range []string{"order_uid:min", "delivery.email:email", "items[0].chrt_id:required"} {
+		if !slices.Contains(fields, want) {
+			t.Errorf("violations %v do not contain %q", fields, want)
+		}
+	}
+}
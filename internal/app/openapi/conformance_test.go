@@ -0,0 +1,347 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	appGraphQL "github.com/AndrejDubinin/wbtech-l0/internal/app/graphql"
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memorycustomersummary "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_customer_summary"
+	memoryidempotency "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_idempotency"
+	memoryorder "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order"
+	memoryorderbody "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_order_body"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/hub"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/customer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/erasure"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/add"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/batchget"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/get"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/lookup"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/query"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/order/search"
+	"github.com/AndrejDubinin/wbtech-l0/internal/usecase/report"
+)
+
+const (
+	fixturesSeed  = 41
+	fixturesCount = 5
+	missingUID    = "missing-order-0000"
+	batchGetLimit = 100
+)
+
+type testCase struct {
+	Name    string
+	Path    string
+	Request *http.Request
+	Status  int
+}
+
+var typeSchemas = map[string]reflect.Type{
+	"Order":                  reflect.TypeFor[domain.Order](),
+	"Violation":              reflect.TypeFor[ingest.Violation](),
+	"Problem":                reflect.TypeFor[appHttp.Problem](),
+	"AddOrderResponse":       reflect.TypeFor[appHttp.AddOrderResponse](),
+	"AddOrdersBulkResponse":  reflect.TypeFor[appHttp.AddOrdersBulkResponse](),
+	"BatchGetOrdersRequest":  reflect.TypeFor[appHttp.BatchGetOrdersRequest](),
+	"BatchGetOrdersResponse": reflect.TypeFor[appHttp.BatchGetOrdersResponse](),
+	"OrderStreamMessage":     reflect.TypeFor[appHttp.OrderStreamMessage](),
+	"GraphQLRequest":         reflect.TypeFor[appGraphQL.Request](),
+	"SearchOrdersResponse":   reflect.TypeFor[appHttp.SearchOrdersResponse](),
+	"CustomerOrdersResponse": reflect.TypeFor[appHttp.CustomerOrdersResponse](),
+	"LookupOrdersResponse":   reflect.TypeFor[appHttp.LookupOrdersResponse](),
+	"ErasureResponse":        reflect.TypeFor[appHttp.ErasureResponse](),
+}
+
+func TestConformance(t *testing.T) {
+	doc, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(typeSchemas)) {
+		t.Run("schema "+name, func(t *testing.T) {
+			for _, diff := range doc.CompareType(name, typeSchemas[name]) {
+				t.Error(diff)
+			}
+		})
+	}
+
+	stored := append(fixtures.EdgeCases(), fixtures.New(fixturesSeed).Orders(fixturesCount)...)
+	handler, err := newHandler(newStore(stored))
+	if err != nil {
+		t.Fatalf("newHandler: %v", err)
+	}
+	cases, err := buildCases(stored)
+	if err != nil {
+		t.Fatalf("buildCases: %v", err)
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if err := check(doc, handler, c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func newHandler(s *store) (http.Handler, error) {
+	renderer, err := appHttp.NewHTMLRenderer("")
+	if err != nil {
+		return nil, fmt.Errorf("appHttp.NewHTMLRenderer: %w", err)
+	}
+	logger := zap.NewNop()
+	cache := memoryorder.New(fixturesCount)
+	decoder := ingest.NewDecoder()
+	idempotency := memoryidempotency.New(time.Minute, fixturesCount)
+	summaries := memorycustomersummary.New(fixturesCount, time.Minute)
+	feed := hub.New(0, 0)
+	addUsecase := add.New(s, cache, summaries, feed)
+
+	queryUsecase := query.New(s, cache)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /order/{order_uid}", appHttp.NewGetOrderHandler(get.New(s, cache), queryUsecase, renderer,
+		memoryorderbody.New(fixturesCount), "", "getOrder", logger))
+	mux.Handle("POST /orders", appHttp.NewAddOrderHandler(addUsecase, decoder, idempotency,
+		"addOrder", logger))
+	mux.Handle("POST /orders:bulk", appHttp.NewAddOrdersBulkHandler(addUsecase, decoder, idempotency,
+		time.Minute, "addOrdersBulk", logger))
+	mux.Handle("POST /orders/batch-get", appHttp.NewBatchGetOrdersHandler(batchget.New(s, cache), batchGetLimit,
+		"batchGetOrders", logger))
+	mux.Handle("GET /orders/search", appHttp.NewSearchOrdersHandler(search.New(s), "searchOrders", logger))
+	mux.Handle("GET /orders/by-track/{track_number}", appHttp.NewLookupOrdersHandler(lookup.New(s, cache),
+		domain.KeyTrackNumber, "track_number", "getOrdersByTrack", logger))
+	mux.Handle("GET /orders/by-transaction/{transaction}", appHttp.NewLookupOrdersHandler(lookup.New(s, cache),
+		domain.KeyTransaction, "transaction", "getOrdersByTransaction", logger))
+	mux.Handle("GET /customers/{customer_id}/orders", appHttp.NewCustomerOrdersHandler(
+		customer.New(s, summaries), "customerOrders", logger))
+	mux.Handle("GET /reports/{report}", appHttp.NewReportHandler(report.New(s, false), "report", logger))
+	graphQLHandler, err := appGraphQL.NewHandler(queryUsecase, "graphQL", logger)
+	if err != nil {
+		return nil, fmt.Errorf("appGraphQL.NewHandler: %w", err)
+	}
+	mux.Handle("POST /graphql", graphQLHandler)
+	mux.Handle("GET /orders/stream", appHttp.NewOrderStreamHandler(feed, "orderStream", logger))
+	mux.Handle("GET /orders/stream/ws", appHttp.NewOrderWebSocketHandler(feed, "orderStreamWebSocket", logger))
+	erasureUsecase := erasure.New(s, cache, summaries, feed, time.Hour)
+	mux.Handle("POST /admin/customers/{customer_id}/anonymize", appHttp.NewAnonymizeCustomerHandler(
+		erasureUsecase, "anonymizeCustomer", logger))
+	mux.Handle("DELETE /admin/orders/{order_uid}", appHttp.NewDeleteOrderHandler(erasureUsecase, "deleteOrder",
+		logger))
+
+	return mux, nil
+}
+
+func buildCases(stored []domain.Order) ([]testCase, error) {
+	var cases []testCase
+	for _, order := range stored {
+		path := "/order/" + order.OrderUID
+		cases = append(cases,
+			testCase{Name: "getOrder masked " + order.OrderUID, Path: "/order/{order_uid}",
+				Request: jsonRequest(http.MethodGet, path, nil), Status: http.StatusOK},
+			testCase{Name: "getOrder with PII " + order.OrderUID, Path: "/order/{order_uid}",
+				Request: withPII(jsonRequest(http.MethodGet, path, nil)), Status: http.StatusOK},
+		)
+	}
+
+	fresh := fixtures.New(fixturesSeed + 1).Order()
+	freshBody, err := json.Marshal(fresh)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	duplicateBody, err := json.Marshal(stored[0])
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	invalid := fresh
+	invalid.OrderUID, invalid.Items, invalid.Delivery.Email = "invalid-order-0001", nil, "not-an-email"
+	invalidBody, err := json.Marshal(invalid)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	bulkBody := bytes.Join([][]byte{freshBody, invalidBody, []byte("{"), duplicateBody}, []byte("\n"))
+
+	cases = append(cases,
+		testCase{Name: "getOrder not found", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/"+missingUID, nil), Status: http.StatusNotFound},
+		testCase{Name: "getOrder invalid uid", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/bad", nil), Status: http.StatusBadRequest},
+		testCase{Name: "getOrder fields", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/"+stored[0].OrderUID+
+				"?fields=order_uid,payment.amount,delivery.city", nil),
+			Status: http.StatusOK},
+		testCase{Name: "getOrder items page", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/"+stored[0].OrderUID+
+				"?fields=order_uid&include=items&items_limit=1&items_offset=1", nil),
+			Status: http.StatusOK},
+		testCase{Name: "getOrder unknown field", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/"+stored[0].OrderUID+"?fields=payment.nope", nil),
+			Status:  http.StatusBadRequest},
+		testCase{Name: "batchGetOrders", Path: "/orders/batch-get",
+			Request: jsonRequest(http.MethodPost, "/orders/batch-get",
+				[]byte(fmt.Sprintf(`{"order_uids":[%q,%q]}`, stored[0].OrderUID, missingUID))),
+			Status: http.StatusOK},
+		testCase{Name: "batchGetOrders empty", Path: "/orders/batch-get",
+			Request: jsonRequest(http.MethodPost, "/orders/batch-get", []byte(`{"order_uids":[]}`)),
+			Status:  http.StatusBadRequest},
+		testCase{Name: "addOrder created", Path: "/orders",
+			Request: jsonRequest(http.MethodPost, "/orders", freshBody), Status: http.StatusCreated},
+		testCase{Name: "addOrder duplicate", Path: "/orders",
+			Request: jsonRequest(http.MethodPost, "/orders", duplicateBody), Status: http.StatusConflict},
+		testCase{Name: "addOrder invalid", Path: "/orders",
+			Request: jsonRequest(http.MethodPost, "/orders", invalidBody), Status: http.StatusUnprocessableEntity},
+		testCase{Name: "addOrder malformed", Path: "/orders",
+			Request: jsonRequest(http.MethodPost, "/orders", []byte("{")), Status: http.StatusBadRequest},
+		testCase{Name: "addOrdersBulk", Path: "/orders:bulk",
+			Request: jsonRequest(http.MethodPost, "/orders:bulk", bulkBody), Status: http.StatusOK},
+		testCase{Name: "searchOrders", Path: "/orders/search",
+			Request: jsonRequest(http.MethodGet, "/orders/search?limit=5&q="+
+				url.QueryEscape(stored[0].Delivery.City), nil),
+			Status: http.StatusOK},
+		testCase{Name: "searchOrders with PII", Path: "/orders/search",
+			Request: withPII(jsonRequest(http.MethodGet, "/orders/search?q="+
+				url.QueryEscape(stored[0].Delivery.Name), nil)),
+			Status: http.StatusOK},
+		testCase{Name: "searchOrders without query", Path: "/orders/search",
+			Request: jsonRequest(http.MethodGet, "/orders/search", nil), Status: http.StatusBadRequest},
+		testCase{Name: "getOrdersByTrack", Path: "/orders/by-track/{track_number}",
+			Request: jsonRequest(http.MethodGet, "/orders/by-track/"+url.PathEscape(stored[0].TrackNumber), nil),
+			Status:  http.StatusOK},
+		testCase{Name: "getOrdersByTrack cached", Path: "/orders/by-track/{track_number}",
+			Request: withPII(jsonRequest(http.MethodGet, "/orders/by-track/"+
+				url.PathEscape(stored[0].TrackNumber), nil)),
+			Status: http.StatusOK},
+		testCase{Name: "getOrdersByTrack not found", Path: "/orders/by-track/{track_number}",
+			Request: jsonRequest(http.MethodGet, "/orders/by-track/MISSINGTRACK", nil), Status: http.StatusNotFound},
+		testCase{Name: "getOrdersByTransaction", Path: "/orders/by-transaction/{transaction}",
+			Request: jsonRequest(http.MethodGet, "/orders/by-transaction/"+
+				url.PathEscape(stored[0].Payment.Transaction), nil),
+			Status: http.StatusOK},
+		testCase{Name: "getOrdersByTransaction not found", Path: "/orders/by-transaction/{transaction}",
+			Request: jsonRequest(http.MethodGet, "/orders/by-transaction/missing-transaction", nil),
+			Status:  http.StatusNotFound},
+		testCase{Name: "customerOrders", Path: "/customers/{customer_id}/orders",
+			Request: jsonRequest(http.MethodGet, "/customers/"+url.PathEscape(stored[0].CustomerID)+
+				"/orders?limit=1", nil),
+			Status: http.StatusOK},
+		testCase{Name: "customerOrders with PII", Path: "/customers/{customer_id}/orders",
+			Request: withPII(jsonRequest(http.MethodGet, "/customers/"+url.PathEscape(stored[0].CustomerID)+
+				"/orders?offset=1", nil)),
+			Status: http.StatusOK},
+		testCase{Name: "customerOrders not found", Path: "/customers/{customer_id}/orders",
+			Request: jsonRequest(http.MethodGet, "/customers/missing-customer/orders", nil),
+			Status:  http.StatusNotFound},
+		testCase{Name: "customerOrders invalid limit", Path: "/customers/{customer_id}/orders",
+			Request: jsonRequest(http.MethodGet, "/customers/"+url.PathEscape(stored[0].CustomerID)+
+				"/orders?limit=0", nil),
+			Status: http.StatusBadRequest},
+		testCase{Name: "report daily", Path: "/reports/{report}",
+			Request: jsonRequest(http.MethodGet, "/reports/daily?tz=Europe/Moscow&date_from=2020-01-01", nil),
+			Status:  http.StatusOK},
+		testCase{Name: "report items per order", Path: "/reports/{report}",
+			Request: jsonRequest(http.MethodGet, "/reports/items-per-order", nil), Status: http.StatusOK},
+		testCase{Name: "report top brands csv", Path: "/reports/{report}",
+			Request: jsonRequest(http.MethodGet, "/reports/top-brands?limit=3&format=csv", nil),
+			Status:  http.StatusOK},
+		testCase{Name: "report unknown", Path: "/reports/{report}",
+			Request: jsonRequest(http.MethodGet, "/reports/nope", nil), Status: http.StatusNotFound},
+		testCase{Name: "report invalid time zone", Path: "/reports/{report}",
+			Request: jsonRequest(http.MethodGet, "/reports/daily?tz=Mars/Olympus", nil), Status: http.StatusBadRequest},
+		testCase{Name: "graphQL order", Path: "/graphql",
+			Request: graphQLRequest(`query($uid: String!) { order(uid: $uid) { orderUid delivery { city } items { brand } } }`,
+				map[string]any{"uid": stored[0].OrderUID}),
+			Status: http.StatusOK},
+		testCase{Name: "graphQL orders page", Path: "/graphql",
+			Request: graphQLRequest(`{ orders(first: 2) { edges { cursor node { payment { amount } } }`+
+				` pageInfo { hasNextPage endCursor } } }`, nil),
+			Status: http.StatusOK},
+		testCase{Name: "graphQL query error", Path: "/graphql",
+			Request: graphQLRequest(`{ orders(first: 0) { pageInfo { hasNextPage } } }`, nil), Status: http.StatusOK},
+		testCase{Name: "graphQL malformed", Path: "/graphql",
+			Request: jsonRequest(http.MethodPost, "/graphql", []byte(`{"query":`)), Status: http.StatusBadRequest},
+		testCase{Name: "orderStream invalid last event id", Path: "/orders/stream",
+			Request: jsonRequest(http.MethodGet, "/orders/stream?last_event_id=x", nil), Status: http.StatusBadRequest},
+		testCase{Name: "orderStreamWebSocket without upgrade", Path: "/orders/stream/ws",
+			Request: jsonRequest(http.MethodGet, "/orders/stream/ws", nil), Status: http.StatusUpgradeRequired},
+	)
+
+	// Erasures change the stored orders, so they run last.
+	last := stored[len(stored)-1]
+	cases = append(cases,
+		testCase{Name: "anonymizeCustomer", Path: "/admin/customers/{customer_id}/anonymize",
+			Request: jsonRequest(http.MethodPost, "/admin/customers/"+url.PathEscape(last.CustomerID)+
+				"/anonymize?reason=request", nil),
+			Status: http.StatusOK},
+		testCase{Name: "anonymizeCustomer not found", Path: "/admin/customers/{customer_id}/anonymize",
+			Request: jsonRequest(http.MethodPost, "/admin/customers/"+url.PathEscape(last.CustomerID)+
+				"/anonymize", nil),
+			Status: http.StatusNotFound},
+		testCase{Name: "deleteOrder", Path: "/admin/orders/{order_uid}",
+			Request: jsonRequest(http.MethodDelete, "/admin/orders/"+last.OrderUID, nil), Status: http.StatusOK},
+		testCase{Name: "deleteOrder already deleted", Path: "/admin/orders/{order_uid}",
+			Request: jsonRequest(http.MethodDelete, "/admin/orders/"+last.OrderUID, nil),
+			Status:  http.StatusNotFound},
+		testCase{Name: "deleteOrder reason too long", Path: "/admin/orders/{order_uid}",
+			Request: jsonRequest(http.MethodDelete, "/admin/orders/"+missingUID+"?reason="+
+				strings.Repeat("x", 501), nil),
+			Status: http.StatusBadRequest},
+		testCase{Name: "getOrder deleted", Path: "/order/{order_uid}",
+			Request: jsonRequest(http.MethodGet, "/order/"+last.OrderUID, nil), Status: http.StatusNotFound},
+	)
+
+	return cases, nil
+}
+
+func check(doc *Document, handler http.Handler, c testCase) error {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, c.Request)
+
+	if rec.Code != c.Status {
+		return fmt.Errorf("status %d, want %d: %s", rec.Code, c.Status, strings.TrimSpace(rec.Body.String()))
+	}
+
+	contentType := rec.Header().Get("Content-Type")
+	schema, err := doc.ResponseSchema(c.Request.Method, c.Path, rec.Code, contentType)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(contentType, "json") {
+		return nil
+	}
+	return doc.Validate(schema, rec.Body.Bytes())
+}
+
+func jsonRequest(method, path string, body []byte) *http.Request {
+	r := httptest.NewRequest(method, path, bytes.NewReader(body))
+	r.Header.Set("Accept", "application/json")
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func graphQLRequest(query string, variables map[string]any) *http.Request {
+	body, err := json.Marshal(appGraphQL.Request{Query: query, Variables: variables})
+	if err != nil {
+		panic(err)
+	}
+	return jsonRequest(http.MethodPost, "/graphql", body)
+}
+
+func withPII(r *http.Request) *http.Request {
+	return r.WithContext(auth.WithPrincipal(r.Context(), auth.Anonymous))
+}
@@ -0,0 +1,234 @@
+// Package openapi holds the OpenAPI 3 contract of the HTTP API.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	refPrefix   = "#/components/schemas/"
+	maxReported = 10
+)
+
+var (
+	//go:embed openapi.json
+	Spec []byte
+	//go:embed docs.html
+	DocsPage []byte
+
+	ErrUndocumented  = errors.New("not documented")
+	ErrNonConformant = errors.New("does not conform to the schema")
+)
+
+type (
+	Schema map[string]any
+
+	Document struct {
+		raw       map[string]any
+		schemas   map[string]any
+		responses map[string]any
+	}
+)
+
+func Load() (*Document, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(Spec, &raw); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	components, _ := raw["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	responses, _ := components["responses"].(map[string]any)
+
+	return &Document{
+		raw:       raw,
+		schemas:   schemas,
+		responses: responses,
+	}, nil
+}
+
+func (d *Document) SchemaByName(name string) (Schema, error) {
+	schema, ok := d.schemas[name].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema %s: %w", name, ErrUndocumented)
+	}
+	return schema, nil
+}
+
+// ResponseSchema takes path as written in the document, e.g. /order/{order_uid}.
+func (d *Document) ResponseSchema(method, path string, status int, contentType string) (Schema, error) {
+	op := fmt.Sprintf("%s %s %d", method, path, status)
+
+	paths, _ := d.raw["paths"].(map[string]any)
+	item, _ := paths[path].(map[string]any)
+	operation, ok := item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, ErrUndocumented)
+	}
+
+	responses, _ := operation["responses"].(map[string]any)
+	response, ok := responses[strconv.Itoa(status)].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, ErrUndocumented)
+	}
+	if ref, isRef := response["$ref"].(string); isRef {
+		response, _ = d.responses[strings.TrimPrefix(ref, "#/components/responses/")].(map[string]any)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: content type %q: %w", op, contentType, err)
+	}
+	content, _ := response["content"].(map[string]any)
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s %s: %w", op, mediaType, ErrUndocumented)
+	}
+	schema, _ := media["schema"].(map[string]any)
+
+	return schema, nil
+}
+
+// Validate supports only the subset of JSON schema the document uses.
+func (d *Document) Validate(schema Schema, body []byte) error {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	var violations []string
+	d.validate(schema, value, "$", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	slices.Sort(violations)
+	if len(violations) > maxReported {
+		violations = append(violations[:maxReported], fmt.Sprintf("and %d more", len(violations)-maxReported))
+	}
+	return fmt.Errorf("%w: %s", ErrNonConformant, strings.Join(violations, "; "))
+}
+
+func (d *Document) resolve(schema map[string]any) map[string]any {
+	for {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		schema, _ = d.schemas[strings.TrimPrefix(ref, refPrefix)].(map[string]any)
+	}
+}
+
+//nolint:cyclop // one case per JSON type
+func (d *Document) validate(schema map[string]any, value any, at string, violations *[]string) {
+	schema = d.resolve(schema)
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		d.validateAnyOf(anyOf, value, at, violations)
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !contains(enum, value) {
+		fail("%v is not one of %v", value, enum)
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("expected object, got %T", value)
+			return
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, present := object[name.(string)]; !present {
+				fail("missing required property %q", name)
+			}
+		}
+		for name, v := range object {
+			property, documented := properties[name].(map[string]any)
+			if !documented {
+				if schema["additionalProperties"] == false {
+					fail("undocumented property %q", name)
+				}
+				continue
+			}
+			d.validate(property, v, at+"."+name, violations)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			fail("expected array, got %T", value)
+			return
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, v := range array {
+			d.validate(items, v, fmt.Sprintf("%s[%d]", at, i), violations)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("expected string, got %T", value)
+			return
+		}
+		if minLength, ok := schema["minLength"].(float64); ok && float64(len([]rune(s))) < minLength {
+			fail("shorter than %v", minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && float64(len([]rune(s))) > maxLength {
+			fail("longer than %v", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err != nil || !re.MatchString(s) {
+				fail("%q does not match %s", s, pattern)
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			fail("expected integer, got %v", value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("expected number, got %T", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %T", value)
+		}
+	}
+}
+
+func (d *Document) validateAnyOf(alternatives []any, value any, at string, violations *[]string) {
+	var closest []string
+	for i, alternative := range alternatives {
+		schema, _ := alternative.(map[string]any)
+		var found []string
+		d.validate(schema, value, at, &found)
+		if len(found) == 0 {
+			return
+		}
+		if i == 0 || len(found) < len(closest) {
+			closest = found
+		}
+	}
+	*violations = append(*violations, closest...)
+}
+
+func contains(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,391 @@
+package openapi
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	store struct {
+		mx       sync.Mutex
+		orders   map[string]domain.Order
+		deleted  map[string]deletedOrder
+		erasures int64
+	}
+	deletedOrder struct {
+		order     domain.Order
+		deletedAt time.Time
+	}
+)
+
+func newStore(orders []domain.Order) *store {
+	s := &store{
+		orders:  make(map[string]domain.Order, len(orders)),
+		deleted: make(map[string]deletedOrder),
+	}
+	for _, order := range orders {
+		s.orders[order.OrderUID] = order
+	}
+	return s
+}
+
+func (s *store) AddOrder(_ context.Context, order domain.Order) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if _, inMap := s.orders[order.OrderUID]; inMap {
+		return fmt.Errorf("%w: %s", domain.ErrOrderAlreadyExists, order.OrderUID)
+	}
+	s.orders[order.OrderUID] = order
+	return nil
+}
+
+func (s *store) GetOrder(_ context.Context, orderUID string) (*domain.Order, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	order, inMap := s.orders[orderUID]
+	if !inMap {
+		return nil, nil
+	}
+	return &order, nil
+}
+
+func (s *store) GetOrdersByUIDs(_ context.Context, orderUIDs []string) (map[string]*domain.Order, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	found := make(map[string]*domain.Order)
+	for _, uid := range orderUIDs {
+		if order, inMap := s.orders[uid]; inMap {
+			found[uid] = &order
+		}
+	}
+	return found, nil
+}
+
+func (s *store) GetOrdersParts(ctx context.Context, orderUIDs []string, _ domain.OrderParts,
+) (map[string]*domain.Order, error) {
+	return s.GetOrdersByUIDs(ctx, orderUIDs)
+}
+
+func (s *store) GetOrderItems(_ context.Context, orderUID string, limit, offset int,
+) ([]domain.Item, int, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	items := s.orders[orderUID].Items
+	start := min(offset, len(items))
+	return items[start:min(start+limit, len(items))], len(items), nil
+}
+
+func (s *store) ListOrders(_ context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+	limit int, _ domain.OrderParts,
+) ([]*domain.Order, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	listed := make([]*domain.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		if filter.Match(&order) && (after == nil || before(domain.CursorOf(&order), *after)) {
+			listed = append(listed, &order)
+		}
+	}
+	slices.SortFunc(listed, func(a, b *domain.Order) int {
+		if before(domain.CursorOf(a), domain.CursorOf(b)) {
+			return 1
+		}
+		return -1
+	})
+	return listed[:min(len(listed), limit)], nil
+}
+
+func (s *store) SearchOrders(_ context.Context, search domain.OrderSearch) ([]domain.OrderHit, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	var hits []domain.OrderHit
+	for _, order := range s.orders {
+		fields := []string{order.Delivery.City}
+		if search.PII {
+			fields = append(fields, order.Delivery.Name, order.Delivery.Email, order.Delivery.Phone,
+				order.Delivery.Address)
+		}
+		for _, item := range order.Items {
+			fields = append(fields, item.Name, item.Brand)
+		}
+		rank := 0
+		for _, field := range fields {
+			for _, term := range search.Terms() {
+				if strings.Contains(strings.ToLower(field), term) {
+					rank++
+				}
+			}
+		}
+		if rank > 0 {
+			hits = append(hits, domain.OrderHit{Order: &order, Rank: float64(rank)})
+		}
+	}
+	slices.SortFunc(hits, func(a, b domain.OrderHit) int {
+		if a.Rank != b.Rank {
+			return cmp.Compare(b.Rank, a.Rank)
+		}
+		return strings.Compare(a.Order.OrderUID, b.Order.OrderUID)
+	})
+	start := min(search.Offset, len(hits))
+	return hits[start:min(start+search.Limit, len(hits))], nil
+}
+
+func (s *store) Report(_ context.Context, kind domain.ReportKind, query domain.ReportQuery,
+) (*domain.Report, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	type group struct {
+		key             []any
+		orders, revenue int64
+	}
+	groups := map[string]*group{}
+	brands := map[string]int64{}
+	var orders, items int64
+	for _, order := range s.orders {
+		if !query.Filter.Match(&order) {
+			continue
+		}
+		orders++
+		items += int64(len(order.Items))
+		for _, item := range order.Items {
+			brands[item.Brand]++
+		}
+
+		var key []any
+		switch kind {
+		case domain.ReportDaily:
+			key = []any{order.DateCreated.In(query.Location).Format(time.DateOnly), order.Payment.Currency}
+		case domain.ReportDeliveryServices:
+			key = []any{order.DeliveryService, order.Payment.Currency}
+		case domain.ReportProviders:
+			key = []any{order.Payment.Provider, order.Payment.Currency}
+		default:
+			key = []any{order.Payment.Currency}
+		}
+		g, inMap := groups[fmt.Sprint(key...)]
+		if !inMap {
+			g = &group{key: key}
+			groups[fmt.Sprint(key...)] = g
+		}
+		g.orders++
+		g.revenue += int64(order.Payment.Amount)
+	}
+
+	report := &domain.Report{Rows: [][]any{}}
+	switch kind {
+	case domain.ReportDaily:
+		report.Columns = []string{"day", "currency", "orders", "revenue"}
+	case domain.ReportDeliveryServices:
+		report.Columns = []string{"delivery_service", "currency", "orders", "revenue"}
+	case domain.ReportProviders:
+		report.Columns = []string{"provider", "currency", "orders", "revenue"}
+	case domain.ReportCurrencies:
+		report.Columns = []string{"currency", "orders", "revenue"}
+	case domain.ReportTopBrands:
+		report.Columns = []string{"brand", "items"}
+		for _, brand := range slices.Sorted(maps.Keys(brands)) {
+			report.Rows = append(report.Rows, []any{brand, brands[brand]})
+		}
+		slices.SortStableFunc(report.Rows, func(a, b []any) int {
+			return cmp.Compare(b[1].(int64), a[1].(int64))
+		})
+		report.Rows = report.Rows[:min(len(report.Rows), query.Limit)]
+		return report, nil
+	case domain.ReportItemsPerOrder:
+		report.Columns = []string{"orders", "items", "items_per_order"}
+		report.Rows = append(report.Rows, []any{orders, items, float64(items) / float64(max(orders, 1))})
+		return report, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownReport, kind)
+	}
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[name]
+		report.Rows = append(report.Rows, append(g.key, g.orders, g.revenue))
+	}
+	return report, nil
+}
+
+func (s *store) RefreshReports(context.Context) error {
+	return nil
+}
+
+func (s *store) GetCustomerOrders(ctx context.Context, customerID string, limit, offset int,
+) ([]*domain.Order, error) {
+	listed, err := s.ListOrders(ctx, domain.OrderFilter{CustomerID: customerID}, nil, math.MaxInt32,
+		domain.OrderParts{})
+	if err != nil {
+		return nil, err
+	}
+	start := min(offset, len(listed))
+	return listed[start:min(start+limit, len(listed))], nil
+}
+
+func (s *store) GetOrdersByKey(_ context.Context, key domain.OrderKey, limit int) ([]*domain.Order, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	var found []*domain.Order
+	for _, order := range s.orders {
+		if slices.Contains(domain.KeysOf(&order), key) {
+			found = append(found, &order)
+		}
+	}
+	slices.SortFunc(found, func(a, b *domain.Order) int {
+		if before(domain.CursorOf(a), domain.CursorOf(b)) {
+			return 1
+		}
+		return -1
+	})
+	return found[:min(len(found), limit)], nil
+}
+
+func (s *store) GetCustomerSummary(_ context.Context, customerID string) (*domain.CustomerSummary, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	summary := &domain.CustomerSummary{CustomerID: customerID}
+	spent := map[string]int64{}
+	cities := map[string]int{}
+	lastIn := map[string]time.Time{}
+	for _, order := range s.orders {
+		if order.CustomerID != customerID {
+			continue
+		}
+		if summary.Orders == 0 || order.DateCreated.Before(summary.FirstOrderAt) {
+			summary.FirstOrderAt = order.DateCreated
+		}
+		if summary.Orders == 0 || order.DateCreated.After(summary.LastOrderAt) {
+			summary.LastOrderAt = order.DateCreated
+		}
+		summary.Orders++
+		spent[order.Payment.Currency] += int64(order.Payment.Amount)
+		cities[order.Delivery.City]++
+		if order.DateCreated.After(lastIn[order.Delivery.City]) {
+			lastIn[order.Delivery.City] = order.DateCreated
+		}
+	}
+	if summary.Orders == 0 {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	for _, currency := range slices.Sorted(maps.Keys(spent)) {
+		summary.Spent = append(summary.Spent, domain.CurrencyAmount{Currency: currency, Amount: spent[currency]})
+	}
+	for _, city := range slices.Sorted(maps.Keys(cities)) {
+		top := summary.TopCity
+		if top == "" || cities[city] > cities[top] ||
+			cities[city] == cities[top] && lastIn[city].After(lastIn[top]) {
+			summary.TopCity = city
+		}
+	}
+	return summary, nil
+}
+
+func (s *store) AnonymizeCustomer(_ context.Context, customerID, token string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	erasure := s.erasure(domain.ErasureAnonymize, token, request)
+	erasure.CustomerID = customerID
+	for uid, order := range s.orders {
+		if order.CustomerID == customerID {
+			s.orders[uid] = anonymized(order, token)
+			erasure.OrderUIDs = append(erasure.OrderUIDs, uid)
+		}
+	}
+	for uid, deleted := range s.deleted {
+		if deleted.order.CustomerID == customerID {
+			deleted.order = anonymized(deleted.order, token)
+			s.deleted[uid] = deleted
+			erasure.OrderUIDs = append(erasure.OrderUIDs, uid)
+		}
+	}
+	if len(erasure.OrderUIDs) == 0 {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return erasure, nil
+}
+
+func (s *store) SoftDeleteOrder(_ context.Context, orderUID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	order, inMap := s.orders[orderUID]
+	if !inMap {
+		return nil, domain.ErrOrderNotFound
+	}
+	delete(s.orders, orderUID)
+	s.deleted[orderUID] = deletedOrder{order: order, deletedAt: time.Now()}
+
+	erasure := s.erasure(domain.ErasureDelete, orderUID, request)
+	erasure.OrderUIDs = []string{orderUID}
+	erasure.CustomerID = order.CustomerID
+	return erasure, nil
+}
+
+func (s *store) PurgeDeletedOrders(_ context.Context, deletedBefore time.Time, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	erasure := &domain.Erasure{Action: domain.ErasurePurge}
+	for uid, deleted := range s.deleted {
+		if deleted.deletedAt.Before(deletedBefore) {
+			delete(s.deleted, uid)
+			erasure.OrderUIDs = append(erasure.OrderUIDs, uid)
+		}
+	}
+	if len(erasure.OrderUIDs) > 0 {
+		purged := s.erasure(domain.ErasurePurge, "deleted before "+deletedBefore.UTC().Format(time.RFC3339),
+			request)
+		purged.OrderUIDs = erasure.OrderUIDs
+		erasure = purged
+	}
+	return erasure, nil
+}
+
+// erasure requires s.mx to be held.
+func (s *store) erasure(action domain.ErasureAction, subject string, request domain.ErasureRequest,
+) *domain.Erasure {
+	s.erasures++
+	return &domain.Erasure{
+		ID:          s.erasures,
+		Action:      action,
+		Subject:     subject,
+		RequestedBy: request.RequestedBy,
+		Reason:      request.Reason,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func anonymized(order domain.Order, token string) domain.Order {
+	order.CustomerID = token
+	order.Delivery = domain.AnonymizedDelivery(order.Delivery, token)
+	order.UpdatedAt = time.Now()
+	return order
+}
+
+func before(a, b domain.OrderCursor) bool {
+	if !a.DateCreated.Equal(b.DateCreated) {
+		return a.DateCreated.Before(b.DateCreated)
+	}
+	return a.OrderUID < b.OrderUID
+}
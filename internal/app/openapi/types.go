@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// CompareType lists differences between the named schema and the JSON fields of t.
+func (d *Document) CompareType(name string, t reflect.Type) []string {
+	var diffs []string
+	d.compareType(name, t, &diffs)
+	return diffs
+}
+
+func (d *Document) compareType(name string, t reflect.Type, diffs *[]string) {
+	schema, err := d.SchemaByName(name)
+	if err != nil {
+		*diffs = append(*diffs, err.Error())
+		return
+	}
+	properties, _ := schema["properties"].(map[string]any)
+
+	st := structType(t)
+	if st == nil {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %s is not a struct", name, t))
+		return
+	}
+
+	fields := jsonFields(st)
+	for field, fieldType := range fields {
+		property, ok := properties[field].(map[string]any)
+		if !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: field %q of %s is not documented", name, field, t))
+			continue
+		}
+		if ref := nestedRef(property); ref != "" {
+			if nested := structType(fieldType); nested != nil {
+				d.compareType(ref, nested, diffs)
+			}
+		}
+	}
+
+	for property := range properties {
+		if _, ok := fields[property]; !ok {
+			*diffs = append(*diffs, fmt.Sprintf("%s: property %q has no field in %s", name, property, t))
+		}
+	}
+	slices.Sort(*diffs)
+}
+
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func nestedRef(property map[string]any) string {
+	if items, ok := property["items"].(map[string]any); ok {
+		property = items
+	}
+	ref, _ := property["$ref"].(string)
+	return strings.TrimPrefix(ref, refPrefix)
+}
+
+// structType returns nil for time.Time, which the document describes as a string.
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+	return t
+}
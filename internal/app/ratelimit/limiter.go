@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+)
+
+const idleTTL = 10 * time.Minute
+
+type (
+	entry struct {
+		key      string
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+
+	// Limiter keeps a token bucket per client, at most maxClients of them;
+	// the list is ordered by last use.
+	Limiter struct {
+		limit      rate.Limit
+		burst      int
+		maxClients int
+		mx         sync.Mutex
+		data       map[string]*list.Element
+		list       *list.List
+	}
+)
+
+func New(rps float64, burst, maxClients int) *Limiter {
+	return &Limiter{
+		limit:      rate.Limit(rps),
+		burst:      burst,
+		maxClients: maxClients,
+		data:       make(map[string]*list.Element),
+		list:       list.New(),
+	}
+}
+
+// Key identifies a verified principal by its ID and everyone else by IP.
+func Key(principal auth.Principal, verified bool, ip string) string {
+	if !verified || principal.Method == auth.Anonymous.Method {
+		return "ip:" + ip
+	}
+	return "principal:" + principal.ID()
+}
+
+// Reserve takes a token from the bucket of key and returns how long to wait
+// for one if there is none.
+func (l *Limiter) Reserve(key string) time.Duration {
+	reservation := l.get(key).Reserve()
+	delay := reservation.Delay()
+	if delay > 0 {
+		reservation.Cancel()
+	}
+	return delay
+}
+
+func (l *Limiter) get(key string) *rate.Limiter {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	now := time.Now()
+	for elem := l.list.Front(); elem != nil && now.Sub(elem.Value.(*entry).lastSeen) > idleTTL; elem = l.list.Front() {
+		l.remove(elem)
+	}
+
+	if elem, inMap := l.data[key]; inMap {
+		e := elem.Value.(*entry)
+		e.lastSeen = now
+		l.list.MoveToBack(elem)
+		return e.limiter
+	}
+
+	e := &entry{
+		key:      key,
+		limiter:  rate.NewLimiter(l.limit, l.burst),
+		lastSeen: now,
+	}
+	l.data[key] = l.list.PushBack(e)
+	for l.list.Len() > l.maxClients {
+		l.remove(l.list.Front())
+	}
+	return e.limiter
+}
+
+func (l *Limiter) remove(elem *list.Element) {
+	l.list.Remove(elem)
+	delete(l.data, elem.Value.(*entry).key)
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+)
+
+func TestKey(t *testing.T) {
+	principal := auth.Principal{Subject: "support", Method: "api_key"}
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		verified  bool
+		want      string
+	}{
+		{"verified", principal, true, "principal:api_key:support"},
+		{"unverified", principal, false, "ip:10.0.0.1"},
+		{"anonymous", auth.Anonymous, true, "ip:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.principal, tt.verified, "10.0.0.1"); got != tt.want {
+			t.Errorf("%s: key %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReserve(t *testing.T) {
+	l := New(1, 2, 10)
+
+	for i := range 2 {
+		if delay := l.Reserve("a"); delay != 0 {
+			t.Fatalf("request %d delayed by %v within the burst", i, delay)
+		}
+	}
+	if delay := l.Reserve("a"); delay <= 0 {
+		t.Fatal("request over the burst is allowed")
+	}
+	if delay := l.Reserve("b"); delay != 0 {
+		t.Fatalf("another client delayed by %v", delay)
+	}
+}
+
+func TestRejectedRequestsDoNotConsumeTokens(t *testing.T) {
+	l := New(10, 1, 10)
+	l.Reserve("a")
+	for range 5 {
+		l.Reserve("a")
+	}
+	if delay := l.Reserve("a"); delay > 100*time.Millisecond {
+		t.Fatalf("delay %v grew with rejected requests", delay)
+	}
+}
+
+func TestMaxClients(t *testing.T) {
+	l := New(1, 1, 3)
+	for i := range 5 {
+		l.Reserve(strconv.Itoa(i))
+	}
+	l.Reserve("3")
+
+	if len(l.data) != 3 || l.list.Len() != 3 {
+		t.Fatalf("%d clients tracked, want 3", len(l.data))
+	}
+	for _, key := range []string{"0", "1"} {
+		if _, inMap := l.data[key]; inMap {
+			t.Errorf("least recently used client %s is kept", key)
+		}
+	}
+	if l.list.Back().Value.(*entry).key != "3" {
+		t.Error("the last used client is not at the back")
+	}
+}
@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	maskRune        = '*'
+	wordMask        = "***"
+	phoneKeepPrefix = 1
+	phoneKeepSuffix = 4
+	txKeepSuffix    = 4
+)
+
+// Order returns a masked copy, the order may be cached.
+func Order(order *domain.Order) *domain.Order {
+	masked := *order
+	masked.Delivery = domain.Delivery{
+		Name:    Name(order.Delivery.Name),
+		Phone:   Phone(order.Delivery.Phone),
+		Zip:     order.Delivery.Zip,
+		City:    order.Delivery.City,
+		Address: Name(order.Delivery.Address),
+		Region:  order.Delivery.Region,
+		Email:   Email(order.Delivery.Email),
+	}
+	masked.Payment.Transaction = Transaction(order.Payment.Transaction)
+
+	return &masked
+}
+
+// "+79161231234" -> "+7******1234".
+func Phone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits <= phoneKeepPrefix+phoneKeepSuffix {
+		return strings.Repeat(string(maskRune), utf8.RuneCountInString(phone))
+	}
+
+	b := strings.Builder{}
+	seen := 0
+	for _, r := range phone {
+		if !unicode.IsDigit(r) {
+			if r == '+' {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		seen++
+		if seen <= phoneKeepPrefix || seen > digits-phoneKeepSuffix {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(maskRune)
+		}
+	}
+	return b.String()
+}
+
+// "john@example.com" -> "j***@example.com".
+func Email(email string) string {
+	local, domainPart, found := strings.Cut(email, "@")
+	if !found || local == "" {
+		return wordMask
+	}
+	first, _ := utf8.DecodeRuneInString(local)
+	return string(first) + wordMask + "@" + domainPart
+}
+
+// "Ivan Petrov" -> "I*** P***".
+func Name(name string) string {
+	words := strings.Fields(name)
+	for i, w := range words {
+		first, _ := utf8.DecodeRuneInString(w)
+		words[i] = string(first) + wordMask
+	}
+	return strings.Join(words, " ")
+}
+
+// "b563feb7b2b84b6test" -> "***test".
+func Transaction(tx string) string {
+	runes := []rune(tx)
+	if len(runes) <= txKeepSuffix {
+		return wordMask
+	}
+	return wordMask + string(runes[len(runes)-txKeepSuffix:])
+}
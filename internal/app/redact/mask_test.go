@@ -0,0 +1,78 @@
+package redact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func TestMasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		mask     func(string) string
+		in, want string
+	}{
+		{"phone", Phone, "+79161231234", "+7******1234"},
+		{"phone with separators", Phone, "+7 (916) 123-12-34", "+7******1234"},
+		{"short phone", Phone, "12345", "*****"},
+		{"email", Email, "john@example.com", "j***@example.com"},
+		{"unicode email", Email, "ёлка@пример.рф", "ё***@пример.рф"},
+		{"not an email", Email, "john", "***"},
+		{"name", Name, "Ivan  Petrov", "I*** P***"},
+		{"unicode name", Name, "Анна-Мария Щербакова", "А*** Щ***"},
+		{"transaction", Transaction, "b563feb7b2b84b6test", "***test"},
+		{"short transaction", Transaction, "test", "***"},
+		{"string", String, "call +79161231234 or mail john@example.com", "call +7******1234 or mail j***@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mask(tt.in); got != tt.want {
+				t.Errorf("%q -> %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderDoesNotModifyOriginal(t *testing.T) {
+	order := fixtures.New(1).Order()
+	original := order.Delivery
+
+	masked := Order(&order)
+	if order.Delivery != original {
+		t.Fatal("the original order was modified")
+	}
+	if masked.Delivery.Phone != Phone(original.Phone) || masked.Delivery.Email != Email(original.Email) ||
+		masked.Delivery.Name != Name(original.Name) || masked.Delivery.Address != Name(original.Address) ||
+		masked.Payment.Transaction != Transaction(order.Payment.Transaction) {
+		t.Errorf("masked %+v", masked.Delivery)
+	}
+	if masked.Delivery.City != original.City || len(masked.Items) != len(order.Items) {
+		t.Error("non-personal data was changed")
+	}
+}
+
+func TestVisible(t *testing.T) {
+	order := fixtures.New(1).Order()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		masked bool
+	}{
+		{"no principal", context.Background(), true},
+		{"orders:read", auth.WithPrincipal(context.Background(),
+			auth.Principal{Scopes: []string{auth.ScopeOrdersRead}}), true},
+		{"orders:read:pii", auth.WithPrincipal(context.Background(),
+			auth.Principal{Scopes: []string{auth.ScopeOrdersReadPII}}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visible := Visible(tt.ctx, &order)
+			if masked := visible.Delivery.Email != order.Delivery.Email; masked != tt.masked {
+				t.Errorf("masked %v, want %v", masked, tt.masked)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package redact
+
+import (
+	"context"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func Visible(ctx context.Context, order *domain.Order) *domain.Order {
+	if PIIAllowed(ctx) {
+		return order
+	}
+	return Order(order)
+}
+
+func PIIAllowed(ctx context.Context) bool {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	return ok && principal.HasScope(auth.ScopeOrdersReadPII)
+}
@@ -0,0 +1,205 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const EncoderName = "redacted-json"
+
+var (
+	emailRe = regexp.MustCompile(`[\p{L}\p{N}._%+\-]+@[\p{L}\p{N}.\-]+\.\p{L}{2,}`)
+	phoneRe = regexp.MustCompile(`\+\d[\d\-\s()]{8,16}\d|\b[78]\d{10}\b`)
+
+	piiKeys = map[string]func(string) string{
+		"name":        Name,
+		"phone":       Phone,
+		"email":       Email,
+		"address":     Name,
+		"transaction": Transaction,
+	}
+)
+
+type (
+	// redactingEncoder masks PII in the message, in fields and in logger.With context.
+	redactingEncoder struct {
+		objectEncoder
+		encoder zapcore.Encoder
+	}
+	objectEncoder struct {
+		zapcore.ObjectEncoder
+	}
+	arrayEncoder struct {
+		zapcore.ArrayEncoder
+		key string
+	}
+
+	redactedObject struct {
+		zapcore.ObjectMarshaler
+	}
+	redactedArray struct {
+		zapcore.ArrayMarshaler
+		key string
+	}
+)
+
+func RegisterEncoder() error {
+	return zap.RegisterEncoder(EncoderName, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return NewEncoder(zapcore.NewJSONEncoder(cfg)), nil
+	})
+}
+
+func NewEncoder(enc zapcore.Encoder) zapcore.Encoder {
+	return redactingEncoder{
+		objectEncoder: objectEncoder{ObjectEncoder: enc},
+		encoder:       enc,
+	}
+}
+
+// String masks emails and phone numbers found anywhere in s.
+func String(s string) string {
+	s = emailRe.ReplaceAllStringFunc(s, Email)
+	return phoneRe.ReplaceAllStringFunc(s, Phone)
+}
+
+func (e redactingEncoder) Clone() zapcore.Encoder {
+	return NewEncoder(e.encoder.Clone())
+}
+
+func (e redactingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = String(ent.Message)
+
+	redacted := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		redacted[i] = Field(f)
+	}
+	return e.encoder.EncodeEntry(ent, redacted)
+}
+
+func (e objectEncoder) AddString(key, value string) {
+	e.ObjectEncoder.AddString(key, redactValue(key, value))
+}
+
+func (e objectEncoder) AddByteString(key string, value []byte) {
+	e.ObjectEncoder.AddString(key, redactValue(key, string(value)))
+}
+
+func (e objectEncoder) AddReflected(key string, value any) error {
+	return e.ObjectEncoder.AddReflected(key, redactReflected(key, value))
+}
+
+func (e objectEncoder) AddObject(key string, value zapcore.ObjectMarshaler) error {
+	return e.ObjectEncoder.AddObject(key, redactedObject{ObjectMarshaler: value})
+}
+
+func (e objectEncoder) AddArray(key string, value zapcore.ArrayMarshaler) error {
+	return e.ObjectEncoder.AddArray(key, redactedArray{ArrayMarshaler: value, key: key})
+}
+
+func (e arrayEncoder) AppendString(value string) {
+	e.ArrayEncoder.AppendString(redactValue(e.key, value))
+}
+
+func (e arrayEncoder) AppendByteString(value []byte) {
+	e.ArrayEncoder.AppendString(redactValue(e.key, string(value)))
+}
+
+func (e arrayEncoder) AppendReflected(value any) error {
+	return e.ArrayEncoder.AppendReflected(redactReflected(e.key, value))
+}
+
+func (e arrayEncoder) AppendObject(value zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactedObject{ObjectMarshaler: value})
+}
+
+func (e arrayEncoder) AppendArray(value zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactedArray{ArrayMarshaler: value, key: e.key})
+}
+
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(objectEncoder{ObjectEncoder: enc})
+}
+
+func (a redactedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(arrayEncoder{ArrayEncoder: enc, key: a.key})
+}
+
+// Field returns f with PII masked; errors and stringers become strings.
+func Field(f zapcore.Field) zapcore.Field {
+	switch f.Type {
+	case zapcore.StringType:
+		return zap.String(f.Key, redactValue(f.Key, f.String))
+	case zapcore.ByteStringType:
+		b, _ := f.Interface.([]byte)
+		return zap.String(f.Key, redactValue(f.Key, string(b)))
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return zap.String(f.Key, redactValue(f.Key, err.Error()))
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok && s != nil {
+			return zap.String(f.Key, redactValue(f.Key, s.String()))
+		}
+	case zapcore.ReflectType:
+		return zap.Reflect(f.Key, redactReflected(f.Key, f.Interface))
+	case zapcore.ObjectMarshalerType:
+		if m, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
+			return zap.Object(f.Key, redactedObject{ObjectMarshaler: m})
+		}
+	case zapcore.InlineMarshalerType:
+		if m, ok := f.Interface.(zapcore.ObjectMarshaler); ok {
+			return zap.Inline(redactedObject{ObjectMarshaler: m})
+		}
+	case zapcore.ArrayMarshalerType:
+		if m, ok := f.Interface.(zapcore.ArrayMarshaler); ok {
+			return zap.Array(f.Key, redactedArray{ArrayMarshaler: m, key: f.Key})
+		}
+	}
+	return f
+}
+
+func redactValue(key, value string) string {
+	if mask, inMap := piiKeys[strings.ToLower(key)]; inMap {
+		return mask(value)
+	}
+	return String(value)
+}
+
+// redactReflected returns the JSON form of v with PII masked.
+func redactReflected(key string, v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redactValue(key, fmt.Sprintf("%v", v))
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return String(string(data))
+	}
+	return redactJSON(key, generic)
+}
+
+func redactJSON(key string, v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, nested := range value {
+			value[k] = redactJSON(k, nested)
+		}
+		return value
+	case []any:
+		for i, nested := range value {
+			value[i] = redactJSON(key, nested)
+		}
+		return value
+	case string:
+		return redactValue(key, value)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testPhone = "+79161231234"
+	testEmail = "john@example.com"
+	testName  = "Ivan Petrov"
+)
+
+type (
+	testContact struct {
+		name, phone string
+		nested      *testContact
+		emails      []string
+	}
+	testContacts []testContact
+)
+
+func (c testContact) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", c.name)
+	enc.AddByteString("phone", []byte(c.phone))
+	if err := enc.AddReflected("meta", map[string]string{"email": testEmail}); err != nil {
+		return err
+	}
+	if c.nested != nil {
+		if err := enc.AddObject("nested", c.nested); err != nil {
+			return err
+		}
+	}
+	return enc.AddArray("emails", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, email := range c.emails {
+			arr.AppendString(email)
+		}
+		return nil
+	}))
+}
+
+func (c testContacts) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, contact := range c {
+		if err := enc.AppendObject(contact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestLogger() (*zap.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.TimeKey = ""
+	core := zapcore.NewCore(NewEncoder(zapcore.NewJSONEncoder(cfg)), zapcore.AddSync(buf), zapcore.DebugLevel)
+	return zap.New(core), buf
+}
+
+func checkNoPII(t *testing.T, out string) {
+	t.Helper()
+	for _, pii := range []string{testPhone, testEmail, testName, "Petrov", "1231234"} {
+		if strings.Contains(out, pii) {
+			t.Errorf("%q leaked into %s", pii, out)
+		}
+	}
+}
+
+func TestEncoderMasksFields(t *testing.T) {
+	logger, buf := newTestLogger()
+	contact := testContact{name: testName, phone: testPhone, emails: []string{testEmail}}
+	contact.nested = &testContact{name: testName, phone: testPhone, emails: []string{testEmail}}
+
+	logger.Info("order for "+testEmail,
+		zap.String("phone", testPhone),
+		zap.ByteString("note", []byte("call "+testPhone)),
+		zap.Error(errors.New("bad email "+testEmail)),
+		zap.Reflect("delivery", map[string]any{"name": testName, "items": []any{map[string]string{"email": testEmail}}}),
+		zap.Object("contact", contact),
+		zap.Array("contacts", testContacts{contact}),
+		zap.Inline(contact),
+		zap.Strings("email", []string{testEmail}),
+		zap.Dict("dict", zap.String("name", testName)),
+		zap.Int("count", 2),
+	)
+
+	out := buf.String()
+	checkNoPII(t, out)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output %s: %v", out, err)
+	}
+	nested, ok := entry["contact"].(map[string]any)["nested"].(map[string]any)
+	if !ok {
+		t.Fatalf("contact is not a nested object: %s", out)
+	}
+	if nested["phone"] != Phone(testPhone) {
+		t.Errorf("nested phone %v", nested["phone"])
+	}
+	if entry["name"] != Name(testName) {
+		t.Errorf("inline name %v", entry["name"])
+	}
+	if entry["count"] != float64(2) {
+		t.Errorf("count %v", entry["count"])
+	}
+}
+
+func TestEncoderMasksWithContext(t *testing.T) {
+	logger, buf := newTestLogger()
+
+	logger.With(
+		zap.String("email", testEmail),
+		zap.Object("contact", testContact{name: testName, phone: testPhone, emails: []string{testEmail}}),
+		zap.Array("contacts", testContacts{{name: testName}}),
+		zap.Any("delivery", map[string]string{"phone": testPhone}),
+	).Info("with")
+
+	checkNoPII(t, buf.String())
+	if !strings.Contains(buf.String(), Email(testEmail)) {
+		t.Errorf("masked email missing: %s", buf.String())
+	}
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	// The runtime image has no zoneinfo.
+	_ "time/tzdata"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	ParamTimezone = "tz"
+	ParamLimit    = "limit"
+	ParamFormat   = "format"
+
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+type (
+	Encoder interface {
+		Encode(w io.Writer, kind domain.ReportKind, query domain.ReportQuery, report *domain.Report) error
+		ContentType() string
+	}
+
+	Document struct {
+		Report   domain.ReportKind `json:"report"`
+		Timezone string            `json:"timezone"`
+		Columns  []string          `json:"columns"`
+		Rows     []Row             `json:"rows"`
+	}
+	// Row keeps the keys in column order.
+	Row struct {
+		columns []string
+		values  []any
+	}
+
+	jsonEncoder struct{}
+	csvEncoder  struct{}
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown report format")
+	ErrInvalidQuery  = errors.New("invalid report query")
+)
+
+// ParseQuery reads dates without a time as midnights in tz.
+func ParseQuery(get func(name string) string) (domain.ReportQuery, error) {
+	query := domain.ReportQuery{
+		Location: time.UTC,
+		Limit:    defaultLimit,
+	}
+
+	if tz := get(ParamTimezone); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return domain.ReportQuery{}, fmt.Errorf("%w: unknown %s %q", ErrInvalidQuery, ParamTimezone, tz)
+		}
+		query.Location = loc
+	}
+
+	if limit := get(ParamLimit); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxLimit {
+			return domain.ReportQuery{}, fmt.Errorf("%w: %s must be between 1 and %d", ErrInvalidQuery, ParamLimit,
+				maxLimit)
+		}
+		query.Limit = n
+	}
+
+	filter, err := export.ParseFilterIn(get, query.Location)
+	if err != nil {
+		return domain.ReportQuery{}, err
+	}
+	query.Filter = filter
+
+	return query, nil
+}
+
+func ParseKind(name string) (domain.ReportKind, error) {
+	for _, kind := range domain.ReportKinds {
+		if string(kind) == name {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", domain.ErrUnknownReport, name)
+}
+
+func NewEncoder(format string) (Encoder, error) {
+	switch format {
+	case FormatJSON, "":
+		return jsonEncoder{}, nil
+	case FormatCSV:
+		return csvEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func (jsonEncoder) Encode(w io.Writer, kind domain.ReportKind, query domain.ReportQuery, report *domain.Report) error {
+	doc := Document{
+		Report:   kind,
+		Timezone: query.Location.String(),
+		Columns:  report.Columns,
+		Rows:     make([]Row, 0, len(report.Rows)),
+	}
+	for _, values := range report.Rows {
+		doc.Rows = append(doc.Rows, Row{columns: report.Columns, values: values})
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (csvEncoder) Encode(w io.Writer, _ domain.ReportKind, _ domain.ReportQuery, report *domain.Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(report.Columns); err != nil {
+		return err
+	}
+	for _, values := range report.Rows {
+		record := make([]string, 0, len(values))
+		for _, value := range values {
+			record = append(record, csvValue(value))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (csvEncoder) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (r Row) MarshalJSON() ([]byte, error) {
+	b := bytes.Buffer{}
+	b.WriteByte('{')
+	for i, column := range r.columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/export"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func parseQuery(t *testing.T, raw string) (domain.ReportQuery, error) {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseQuery(values.Get)
+}
+
+func TestParseQuery(t *testing.T) {
+	query, err := parseQuery(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Location != time.UTC || query.Limit != defaultLimit {
+		t.Errorf("defaults %+v", query)
+	}
+
+	query, err = parseQuery(t, "tz=Europe/Moscow&limit=5&date_from=2026-10-01&delivery_service=meest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Location.String() != "Europe/Moscow" || query.Limit != 5 || query.Filter.DeliveryService != "meest" {
+		t.Errorf("query %+v", query)
+	}
+	if want := time.Date(2026, 9, 30, 21, 0, 0, 0, time.UTC); !query.Filter.CreatedFrom.Equal(want) {
+		t.Errorf("date_from %v, want midnight in the time zone %v", query.Filter.CreatedFrom, want)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"tz=Mars/Olympus", ErrInvalidQuery},
+		{"tz=Local", ErrInvalidQuery},
+		{"limit=0", ErrInvalidQuery},
+		{"limit=101", ErrInvalidQuery},
+		{"limit=ten", ErrInvalidQuery},
+		{"date_to=tomorrow", export.ErrInvalidFilter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if _, err := parseQuery(t, tt.raw); !errors.Is(err, tt.want) {
+				t.Errorf("err %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for _, kind := range domain.ReportKinds {
+		if got, err := ParseKind(string(kind)); err != nil || got != kind {
+			t.Errorf("ParseKind(%q) = %q, %v", kind, got, err)
+		}
+	}
+	if _, err := ParseKind("weekly"); !errors.Is(err, domain.ErrUnknownReport) {
+		t.Errorf("err %v, want ErrUnknownReport", err)
+	}
+}
+
+func testReport() *domain.Report {
+	return &domain.Report{
+		Columns: []string{"currency", "orders", "revenue"},
+		Rows: [][]any{
+			{"RUB", int64(3), 1500.5},
+			{"USD, \"cents\"", int64(1), nil},
+		},
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	query := domain.ReportQuery{Location: time.UTC}
+
+	tests := []struct {
+		format, contentType, want string
+	}{
+		{"", "application/json", `{"report":"currencies","timezone":"UTC",` +
+			`"columns":["currency","orders","revenue"],"rows":[` +
+			`{"currency":"RUB","orders":3,"revenue":1500.5},{"currency":"USD, \"cents\"","orders":1,"revenue":null}]}` +
+			"\n"},
+		{FormatCSV, "text/csv; charset=utf-8", "currency,orders,revenue\nRUB,3,1500.5\n\"USD, \"\"cents\"\"\",1,\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			encoder, err := NewEncoder(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := encoder.ContentType(); got != tt.contentType {
+				t.Errorf("ContentType %q", got)
+			}
+			b := bytes.Buffer{}
+			if err := encoder.Encode(&b, domain.ReportCurrencies, query, testReport()); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("encoded\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewEncoder("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("err %v, want ErrUnknownFormat", err)
+	}
+}
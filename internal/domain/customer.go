@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const maxCustomerIDLen = 255
+
+var ErrCustomerNotFound = errors.New("customer not found")
+
+func ValidCustomerID(id string) bool {
+	return id != "" && len(id) <= maxCustomerIDLen
+}
+
+type CustomerSummary struct {
+	CustomerID string
+	Orders     int
+	// Spent is sorted by currency.
+	Spent        []CurrencyAmount
+	FirstOrderAt time.Time
+	LastOrderAt  time.Time
+	// TopCity ties go to the city delivered to last.
+	TopCity string
+}
+
+type CurrencyAmount struct {
+	Currency string
+	Amount   int64
+}
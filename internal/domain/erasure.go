@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	anonymousTokenPrefix = "anon-"
+	anonymousTokenBytes  = 16
+	anonymousEmailDomain = "@anonymized.invalid"
+)
+
+type ErasureAction string
+
+const (
+	ErasureAnonymize ErasureAction = "anonymize"
+	ErasureDelete    ErasureAction = "delete"
+	ErasurePurge     ErasureAction = "purge"
+)
+
+type ErasureRequest struct {
+	RequestedBy string
+	Reason      string
+}
+
+type Erasure struct {
+	ID          int64
+	Action      ErasureAction
+	Subject     string // order UID or the new customer token
+	RequestedBy string
+	Reason      string
+	CreatedAt   time.Time
+	// Not stored in the audit.
+	OrderUIDs  []string
+	CustomerID string
+}
+
+func NewAnonymousToken() string {
+	b := make([]byte, anonymousTokenBytes)
+	_, _ = rand.Read(b)
+	return anonymousTokenPrefix + hex.EncodeToString(b)
+}
+
+// AnonymizedDelivery keeps city and region for statistics.
+func AnonymizedDelivery(delivery Delivery, token string) Delivery {
+	delivery.Name = token
+	delivery.Phone = token
+	delivery.Zip = token
+	delivery.Address = token
+	delivery.Email = token + anonymousEmailDomain
+	return delivery
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type OrderFilter struct {
+	CustomerID      string
+	DeliveryService string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+}
+
+type OrderWriter interface {
+	Write(order *Order) error
+	Flush() error
+}
+
+// CreatedTo is exclusive.
+func (f OrderFilter) Match(order *Order) bool {
+	switch {
+	case f.CustomerID != "" && order.CustomerID != f.CustomerID:
+		return false
+	case f.DeliveryService != "" && order.DeliveryService != f.DeliveryService:
+		return false
+	case !f.CreatedFrom.IsZero() && order.DateCreated.Before(f.CreatedFrom):
+		return false
+	case !f.CreatedTo.IsZero() && !order.DateCreated.Before(f.CreatedTo):
+		return false
+	}
+	return true
+}
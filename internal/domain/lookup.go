@@ -0,0 +1,38 @@
+package domain
+
+const maxOrderKeyLen = 255
+
+type OrderKeyKind string
+
+const (
+	KeyTrackNumber OrderKeyKind = "track_number"
+	KeyTransaction OrderKeyKind = "transaction"
+)
+
+// OrderKey may be shared by several orders.
+type OrderKey struct {
+	Kind  OrderKeyKind
+	Value string
+}
+
+func (k OrderKey) Valid() bool {
+	return k.Value != "" && len(k.Value) <= maxOrderKeyLen
+}
+
+func KeysOf(order *Order) []OrderKey {
+	keys := []OrderKey{{Kind: KeyTrackNumber, Value: order.TrackNumber}}
+	for _, item := range order.Items {
+		keys = append(keys, OrderKey{Kind: KeyTrackNumber, Value: item.TrackNumber})
+	}
+	keys = append(keys, OrderKey{Kind: KeyTransaction, Value: order.Payment.Transaction})
+
+	distinct := keys[:0]
+	seen := make(map[OrderKey]bool, len(keys))
+	for _, key := range keys {
+		if key.Value != "" && !seen[key] {
+			seen[key] = true
+			distinct = append(distinct, key)
+		}
+	}
+	return distinct
+}
@@ -2,10 +2,22 @@ package domain
 
 import (
 	"errors"
+	"regexp"
 	"time"
 )
 
-var ErrOrderNotFound = errors.New("order not found")
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrOrderAlreadyExists = errors.New("order already exists")
+
+	orderUIDPattern = regexp.MustCompile("^[a-zA-Z0-9_-]{8,64}$")
+)
+
+// ValidOrderUID reports whether uid may be looked up: 8-64 characters of
+// letters, digits, "_" and "-".
+func ValidOrderUID(uid string) bool {
+	return orderUIDPattern.MatchString(uid)
+}
 
 type Order struct {
 	OrderUID          string    `json:"order_uid" validate:"required,min=8,max=64"`
@@ -22,6 +34,15 @@ type Order struct {
 	SmID              int       `json:"sm_id" validate:"required,gt=0"`
 	DateCreated       time.Time `json:"date_created" validate:"required"`
 	OofShard          string    `json:"oof_shard" validate:"required"`
+	UpdatedAt         time.Time `json:"-"`
+}
+
+// LastModified accounts for changes after ingestion, like anonymisation.
+func (o *Order) LastModified() time.Time {
+	if o.UpdatedAt.After(o.DateCreated) {
+		return o.UpdatedAt
+	}
+	return o.DateCreated
 }
 
 type Delivery struct {
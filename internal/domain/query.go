@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// OrderParts not selected are left zero.
+type OrderParts struct {
+	Delivery bool
+	Payment  bool
+	Items    bool
+}
+
+// OrderCursor orders by DateCreated, then OrderUID, both descending.
+type OrderCursor struct {
+	DateCreated time.Time
+	OrderUID    string
+}
+
+func CursorOf(order *Order) OrderCursor {
+	return OrderCursor{
+		DateCreated: order.DateCreated,
+		OrderUID:    order.OrderUID,
+	}
+}
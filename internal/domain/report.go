@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type ReportKind string
+
+const (
+	ReportDaily            ReportKind = "daily"
+	ReportDeliveryServices ReportKind = "delivery-services"
+	ReportProviders        ReportKind = "providers"
+	ReportCurrencies       ReportKind = "currencies"
+	ReportTopBrands        ReportKind = "top-brands"
+	ReportItemsPerOrder    ReportKind = "items-per-order"
+)
+
+var ErrUnknownReport = errors.New("unknown report")
+
+var ReportKinds = []ReportKind{
+	ReportDaily, ReportDeliveryServices, ReportProviders, ReportCurrencies, ReportTopBrands, ReportItemsPerOrder,
+}
+
+type ReportQuery struct {
+	Filter   OrderFilter
+	Location *time.Location
+	Limit    int
+	// Precomputed allows reading the report views when the filter fits them.
+	Precomputed bool
+}
+
+// Report values are strings, int64 or float64.
+type Report struct {
+	Columns []string
+	Rows    [][]any
+}
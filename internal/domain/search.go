@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minPartialMatch is the shortest text the trigram indexes can match.
+const minPartialMatch = 3
+
+type OrderSearch struct {
+	Text   string
+	Limit  int
+	Offset int
+	// PII allows matching the masked delivery fields: name, phone, email and address.
+	PII bool
+}
+
+type OrderHit struct {
+	Order *Order
+	Rank  float64
+}
+
+// Terms leaves out quotes, "or" and the words excluded with "-".
+func (s OrderSearch) Terms() []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(s.Text)) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		word = strings.Trim(word, `"`)
+		if word != "" && word != "or" {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func (s OrderSearch) PhoneDigits() string {
+	digits := strings.Builder{}
+	for _, r := range s.Text {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case strings.ContainsRune(" +-()", r):
+		default:
+			return ""
+		}
+	}
+	if digits.Len() < minPartialMatch {
+		return ""
+	}
+	return digits.String()
+}
+
+func (s OrderSearch) Token() string {
+	token := strings.TrimSpace(s.Text)
+	if utf8.RuneCountInString(token) < minPartialMatch || strings.ContainsFunc(token, unicode.IsSpace) {
+		return ""
+	}
+	return token
+}
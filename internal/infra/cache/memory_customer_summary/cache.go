@@ -0,0 +1,90 @@
+package memorycustomersummary
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	item struct {
+		summary   *domain.CustomerSummary
+		expiresAt time.Time
+	}
+
+	// LRUCache expires summaries: orders stored by other processes do not invalidate them.
+	LRUCache struct {
+		capacity int
+		ttl      time.Duration
+		mx       sync.Mutex
+		data     map[string]*list.Element
+		list     *list.List
+	}
+)
+
+// New disables caching for a zero capacity or ttl.
+func New(capacity int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		data:     make(map[string]*list.Element),
+		list:     list.New(),
+	}
+}
+
+func (c *LRUCache) Get(customerID string) *domain.CustomerSummary {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	elem, inMap := c.data[customerID]
+	if !inMap {
+		return nil
+	}
+	if time.Now().After(elem.Value.(*item).expiresAt) {
+		c.remove(elem)
+		return nil
+	}
+
+	c.list.MoveToFront(elem)
+	return elem.Value.(*item).summary
+}
+
+func (c *LRUCache) Put(summary *domain.CustomerSummary) {
+	if c.capacity <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	value := &item{summary: summary, expiresAt: time.Now().Add(c.ttl)}
+	if elem, inMap := c.data[summary.CustomerID]; inMap {
+		elem.Value = value
+		c.list.MoveToFront(elem)
+		return
+	}
+
+	c.data[summary.CustomerID] = c.list.PushFront(value)
+
+	if c.list.Len() > c.capacity {
+		if last := c.list.Back(); last != nil {
+			c.remove(last)
+		}
+	}
+}
+
+func (c *LRUCache) Delete(customerID string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, inMap := c.data[customerID]; inMap {
+		c.remove(elem)
+	}
+}
+
+func (c *LRUCache) remove(elem *list.Element) {
+	c.list.Remove(elem)
+	delete(c.data, elem.Value.(*item).summary.CustomerID)
+}
@@ -0,0 +1,76 @@
+package memorycustomersummary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func summary(customerID string, orders int) *domain.CustomerSummary {
+	return &domain.CustomerSummary{CustomerID: customerID, Orders: orders}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(2, time.Hour)
+	c.Put(summary("a", 1))
+	c.Put(summary("b", 1))
+	c.Get("a")
+	c.Put(summary("c", 1))
+
+	if c.Get("b") != nil {
+		t.Error("least recently used summary kept")
+	}
+	if c.Get("a") == nil || c.Get("c") == nil {
+		t.Error("recently used summary evicted")
+	}
+	if c.list.Len() != 2 || len(c.data) != 2 {
+		t.Errorf("list %d, map %d, want 2", c.list.Len(), len(c.data))
+	}
+}
+
+func TestLRUCachePutReplaces(t *testing.T) {
+	c := New(2, time.Hour)
+	c.Put(summary("a", 1))
+	c.Put(summary("a", 2))
+
+	if got := c.Get("a"); got == nil || got.Orders != 2 {
+		t.Errorf("Get %+v, want the replacement", got)
+	}
+	if c.list.Len() != 1 {
+		t.Errorf("list %d, want 1", c.list.Len())
+	}
+}
+
+func TestLRUCacheExpires(t *testing.T) {
+	c := New(2, time.Hour)
+	c.Put(summary("a", 1))
+	c.data["a"].Value.(*item).expiresAt = time.Now().Add(-time.Second)
+
+	if c.Get("a") != nil {
+		t.Error("expired summary returned")
+	}
+	if len(c.data) != 0 || c.list.Len() != 0 {
+		t.Error("expired summary kept")
+	}
+}
+
+func TestLRUCacheDelete(t *testing.T) {
+	c := New(2, time.Hour)
+	c.Put(summary("a", 1))
+	c.Delete("a")
+	c.Delete("missing")
+
+	if c.Get("a") != nil {
+		t.Error("deleted summary returned")
+	}
+}
+
+func TestLRUCacheDisabled(t *testing.T) {
+	for _, c := range []*LRUCache{New(0, time.Hour), New(2, 0)} {
+		c.Put(summary("a", 1))
+		if c.Get("a") != nil {
+			t.Errorf("cache of capacity %d, ttl %v stored a summary", c.capacity, c.ttl)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package memoryidempotency
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	// Response is a stored result of a request made with an Idempotency-Key.
+	Response struct {
+		Status      int
+		ContentType string
+		Body        []byte
+	}
+
+	entry struct {
+		key         string
+		fingerprint string
+		response    *Response
+		expiresAt   time.Time
+	}
+
+	// Cache keeps at most capacity keys; the list is ordered by expiry.
+	Cache struct {
+		ttl      time.Duration
+		capacity int
+		mx       sync.Mutex
+		data     map[string]*list.Element
+		list     *list.List
+	}
+)
+
+var (
+	ErrInProgress          = errors.New("request with this idempotency key is in progress")
+	ErrFingerprintMismatch = errors.New("idempotency key reused with a different request")
+)
+
+func New(ttl time.Duration, capacity int) *Cache {
+	return &Cache{
+		ttl:      ttl,
+		capacity: capacity,
+		data:     make(map[string]*list.Element),
+		list:     list.New(),
+	}
+}
+
+// Begin reserves key for the request with fingerprint. A nil response and nil
+// error mean the caller owns the key and must call Complete or Release.
+func (c *Cache) Begin(key, fingerprint string) (*Response, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	now := time.Now()
+	c.evictExpired(now)
+
+	if elem, inMap := c.data[key]; inMap {
+		e := elem.Value.(*entry)
+		switch {
+		case e.fingerprint != fingerprint:
+			return nil, ErrFingerprintMismatch
+		case e.response == nil:
+			return nil, ErrInProgress
+		default:
+			return e.response, nil
+		}
+	}
+	if c.capacity <= 0 {
+		return nil, nil
+	}
+
+	c.data[key] = c.list.PushBack(&entry{
+		key:         key,
+		fingerprint: fingerprint,
+		expiresAt:   now.Add(c.ttl),
+	})
+	for c.list.Len() > c.capacity {
+		c.remove(c.list.Front())
+	}
+	return nil, nil
+}
+
+func (c *Cache) Complete(key string, response Response) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, inMap := c.data[key]; inMap {
+		e := elem.Value.(*entry)
+		e.response = &response
+		e.expiresAt = time.Now().Add(c.ttl)
+		c.list.MoveToBack(elem)
+	}
+}
+
+// Release frees key without storing a response, so the request can be retried.
+func (c *Cache) Release(key string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, inMap := c.data[key]; inMap {
+		c.remove(elem)
+	}
+}
+
+func (c *Cache) evictExpired(now time.Time) {
+	for elem := c.list.Front(); elem != nil && now.After(elem.Value.(*entry).expiresAt); elem = c.list.Front() {
+		c.remove(elem)
+	}
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	c.list.Remove(elem)
+	delete(c.data, elem.Value.(*entry).key)
+}
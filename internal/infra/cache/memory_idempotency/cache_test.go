@@ -0,0 +1,104 @@
+package memoryidempotency
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheBeginComplete(t *testing.T) {
+	c := New(time.Hour, 10)
+
+	if stored, err := c.Begin("key", "a"); stored != nil || err != nil {
+		t.Fatalf("first Begin = %v, %v", stored, err)
+	}
+	if _, err := c.Begin("key", "a"); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("Begin while in progress: %v, want ErrInProgress", err)
+	}
+	if _, err := c.Begin("key", "b"); !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("Begin with another fingerprint: %v, want ErrFingerprintMismatch", err)
+	}
+
+	c.Complete("key", Response{Status: 201, Body: []byte("created")})
+	stored, err := c.Begin("key", "a")
+	if err != nil || stored == nil || stored.Status != 201 || string(stored.Body) != "created" {
+		t.Fatalf("Begin after Complete = %+v, %v", stored, err)
+	}
+}
+
+func TestCacheRelease(t *testing.T) {
+	c := New(time.Hour, 10)
+
+	if _, err := c.Begin("key", "a"); err != nil {
+		t.Fatal(err)
+	}
+	c.Release("key")
+	if stored, err := c.Begin("key", "b"); stored != nil || err != nil {
+		t.Fatalf("Begin after Release = %v, %v", stored, err)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	c := New(time.Millisecond, 10)
+
+	if _, err := c.Begin("key", "a"); err != nil {
+		t.Fatal(err)
+	}
+	c.Complete("key", Response{Status: 201})
+	time.Sleep(5 * time.Millisecond)
+
+	if stored, err := c.Begin("key", "b"); stored != nil || err != nil {
+		t.Fatalf("Begin after expiry = %v, %v", stored, err)
+	}
+}
+
+func TestCacheCapacity(t *testing.T) {
+	c := New(time.Hour, 2)
+
+	for _, key := range []string{"k1", "k2", "k3"} {
+		if _, err := c.Begin(key, key); err != nil {
+			t.Fatal(err)
+		}
+		c.Complete(key, Response{Status: 201})
+	}
+	if len(c.data) != 2 || c.list.Len() != 2 {
+		t.Fatalf("%d keys kept, want 2", len(c.data))
+	}
+	if stored, _ := c.Begin("k1", "k1"); stored != nil {
+		t.Error("the oldest key was not evicted")
+	}
+	if stored, _ := c.Begin("k3", "k3"); stored == nil {
+		t.Error("the newest key was evicted")
+	}
+}
+
+func TestCacheCompleteKeepsExpiryOrder(t *testing.T) {
+	c := New(time.Hour, 2)
+
+	if _, err := c.Begin("slow", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Begin("fast", "b"); err != nil {
+		t.Fatal(err)
+	}
+	c.Complete("fast", Response{Status: 201})
+	c.Complete("slow", Response{Status: 201})
+	if _, err := c.Begin("next", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := c.Begin("slow", "a"); stored == nil {
+		t.Error("the most recently completed key was evicted")
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := New(time.Hour, 0)
+
+	for range 2 {
+		if stored, err := c.Begin("key", "a"); stored != nil || err != nil {
+			t.Fatalf("Begin = %v, %v", stored, err)
+		}
+		c.Complete("key", Response{Status: 201})
+	}
+}
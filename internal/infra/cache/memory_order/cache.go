@@ -1,23 +1,45 @@
 package memoryorder
 
 import (
+	"cmp"
 	"container/list"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
 )
 
-type LRUCache struct {
-	capacity int64
-	mx       sync.Mutex
-	data     map[string]*list.Element
-	list     *list.List
-}
+// keyLookupTTL bounds key lookups: orders stored by other processes do not reach the cache.
+const keyLookupTTL = 5 * time.Minute
+
+// deletedTTL keeps a request that read an order before its deletion from putting it back.
+const deletedTTL = time.Minute
+
+type (
+	// keyEntry lists every stored order with the key until completeUntil.
+	keyEntry struct {
+		orderUIDs     map[string]struct{}
+		completeUntil time.Time
+	}
+
+	LRUCache struct {
+		capacity int64
+		mx       sync.Mutex
+		data     map[string]*list.Element
+		keys     map[domain.OrderKey]*keyEntry
+		deleted  map[string]time.Time
+		list     *list.List
+	}
+)
 
 func New(capacity int64) *LRUCache {
 	return &LRUCache{
 		capacity: capacity,
 		data:     make(map[string]*list.Element),
+		keys:     make(map[domain.OrderKey]*keyEntry),
+		deleted:  make(map[string]time.Time),
 		list:     list.New(),
 	}
 }
@@ -38,20 +60,142 @@ func (c *LRUCache) Put(order *domain.Order) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
+	c.put(order)
+}
+
+func (c *LRUCache) Delete(orderUID string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	now := time.Now()
+	for uid, deletedAt := range c.deleted {
+		if now.Sub(deletedAt) > deletedTTL {
+			delete(c.deleted, uid)
+		}
+	}
+	c.deleted[orderUID] = now
+
+	if elem, inMap := c.data[orderUID]; inMap {
+		c.list.Remove(elem)
+		c.evicted(elem.Value.(*domain.Order))
+	}
+}
+
+// GetByKey returns ok false unless the cache knows all the orders with key.
+func (c *LRUCache) GetByKey(key domain.OrderKey) (orders []*domain.Order, ok bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	entry, inMap := c.keys[key]
+	if !inMap || time.Now().After(entry.completeUntil) {
+		return nil, false
+	}
+
+	for orderUID := range entry.orderUIDs {
+		elem, inMap := c.data[orderUID]
+		if !inMap {
+			delete(entry.orderUIDs, orderUID)
+			entry.completeUntil = time.Time{}
+			continue
+		}
+		c.list.MoveToFront(elem)
+		orders = append(orders, elem.Value.(*domain.Order))
+	}
+	if entry.completeUntil.IsZero() {
+		return nil, false
+	}
+	slices.SortFunc(orders, newestFirst)
+	return orders, true
+}
+
+// PutByKey takes all the stored orders with key.
+func (c *LRUCache) PutByKey(key domain.OrderKey, orders []*domain.Order) {
+	if len(orders) == 0 {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	entry := c.keyEntry(key)
+	entry.completeUntil = time.Now().Add(keyLookupTTL)
+	for _, order := range orders {
+		if !c.put(order) {
+			entry.completeUntil = time.Time{}
+		}
+	}
+	if len(entry.orderUIDs) == 0 && c.keys[key] == entry {
+		delete(c.keys, key)
+	}
+}
+
+func (c *LRUCache) put(order *domain.Order) bool {
+	if deletedAt, inMap := c.deleted[order.OrderUID]; inMap && time.Since(deletedAt) <= deletedTTL {
+		return false
+	}
 	if elem, inMap := c.data[order.OrderUID]; inMap {
+		c.reindex(elem.Value.(*domain.Order), order)
 		elem.Value = order
 		c.list.MoveToFront(elem)
-		return
+		return true
 	}
 
 	elem := c.list.PushFront(order)
 	c.data[order.OrderUID] = elem
+	for _, key := range domain.KeysOf(order) {
+		c.keyEntry(key).orderUIDs[order.OrderUID] = struct{}{}
+	}
 
 	if int64(c.list.Len()) > c.capacity {
 		last := c.list.Back()
 		if last != nil {
 			c.list.Remove(last)
-			delete(c.data, last.Value.(*domain.Order).OrderUID)
+			c.evicted(last.Value.(*domain.Order))
 		}
 	}
+	return true
+}
+
+func (c *LRUCache) keyEntry(key domain.OrderKey) *keyEntry {
+	entry, inMap := c.keys[key]
+	if !inMap {
+		entry = &keyEntry{orderUIDs: make(map[string]struct{})}
+		c.keys[key] = entry
+	}
+	return entry
+}
+
+// reindex moves a replaced order to the entries of its new keys.
+func (c *LRUCache) reindex(old, order *domain.Order) {
+	keys := domain.KeysOf(order)
+	for _, key := range domain.KeysOf(old) {
+		entry, inMap := c.keys[key]
+		if !inMap || slices.Contains(keys, key) {
+			continue
+		}
+		delete(entry.orderUIDs, old.OrderUID)
+		if len(entry.orderUIDs) == 0 {
+			delete(c.keys, key)
+		}
+	}
+	for _, key := range keys {
+		c.keyEntry(key).orderUIDs[order.OrderUID] = struct{}{}
+	}
+}
+
+// evicted leaves the entries listing order incomplete: the database still has it.
+func (c *LRUCache) evicted(order *domain.Order) {
+	delete(c.data, order.OrderUID)
+	for _, key := range domain.KeysOf(order) {
+		entry := c.keys[key]
+		delete(entry.orderUIDs, order.OrderUID)
+		entry.completeUntil = time.Time{}
+		if len(entry.orderUIDs) == 0 {
+			delete(c.keys, key)
+		}
+	}
+}
+
+func newestFirst(a, b *domain.Order) int {
+	return cmp.Or(b.DateCreated.Compare(a.DateCreated), strings.Compare(b.OrderUID, a.OrderUID))
 }
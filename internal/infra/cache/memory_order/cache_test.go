@@ -0,0 +1,142 @@
+package memoryorder
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+var (
+	trackA = domain.OrderKey{Kind: domain.KeyTrackNumber, Value: "TRACK-A"}
+	trackB = domain.OrderKey{Kind: domain.KeyTrackNumber, Value: "TRACK-B"}
+)
+
+func order(uid, trackNumber string, created time.Time) *domain.Order {
+	return &domain.Order{OrderUID: uid, TrackNumber: trackNumber, DateCreated: created}
+}
+
+func uids(orders []*domain.Order) []string {
+	var result []string
+	for _, order := range orders {
+		result = append(result, order.OrderUID)
+	}
+	return result
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	c := New(2)
+	c.Put(order("a", "", now))
+	c.Put(order("b", "", now))
+	c.Get("a")
+	c.Put(order("c", "", now))
+
+	if c.Get("b") != nil {
+		t.Error("least recently used order kept")
+	}
+	if c.Get("a") == nil || c.Get("c") == nil {
+		t.Error("recently used order evicted")
+	}
+	if c.list.Len() != 2 || len(c.data) != 2 {
+		t.Errorf("list %d, map %d, want 2", c.list.Len(), len(c.data))
+	}
+}
+
+func TestLRUCacheGetByKey(t *testing.T) {
+	now := time.Now()
+	c := New(10)
+	if _, ok := c.GetByKey(trackA); ok {
+		t.Fatal("unknown key answered")
+	}
+
+	c.Put(order("a", trackA.Value, now))
+	if _, ok := c.GetByKey(trackA); ok {
+		t.Error("key answered from orders put one by one")
+	}
+
+	c.PutByKey(trackA, []*domain.Order{order("a", trackA.Value, now), order("b", trackA.Value, now.Add(time.Hour))})
+	c.Put(order("c", trackA.Value, now))
+	orders, ok := c.GetByKey(trackA)
+	if !ok {
+		t.Fatal("complete key not answered")
+	}
+	if got, want := uids(orders), []string{"b", "c", "a"}; !slices.Equal(got, want) {
+		t.Errorf("orders %v, want %v newest first, then by UID", got, want)
+	}
+
+	c.keys[trackA].completeUntil = now.Add(-time.Second)
+	if _, ok := c.GetByKey(trackA); ok {
+		t.Error("expired key answered")
+	}
+}
+
+func TestLRUCacheGetByKeyIncomplete(t *testing.T) {
+	now := time.Now()
+	c := New(2)
+	c.PutByKey(trackA, []*domain.Order{order("a", trackA.Value, now), order("b", trackA.Value, now)})
+	c.Put(order("c", "", now))
+
+	if _, ok := c.GetByKey(trackA); ok {
+		t.Error("key answered after one of its orders was evicted")
+	}
+
+	c.PutByKey(trackB, []*domain.Order{order("d", trackB.Value, now)})
+	delete(c.data, "d")
+	if _, ok := c.GetByKey(trackB); ok {
+		t.Error("key answered without its order")
+	}
+	if _, inMap := c.keys[trackB].orderUIDs["d"]; inMap {
+		t.Error("missing order kept in the key entry")
+	}
+}
+
+func TestLRUCachePutReplacesKeys(t *testing.T) {
+	now := time.Now()
+	c := New(10)
+	c.PutByKey(trackA, []*domain.Order{order("a", trackA.Value, now)})
+	c.PutByKey(trackB, []*domain.Order{order("b", trackB.Value, now)})
+	c.Put(order("a", trackB.Value, now.Add(time.Hour)))
+
+	if _, inMap := c.keys[trackA]; inMap {
+		t.Error("old key of a replaced order kept")
+	}
+	orders, ok := c.GetByKey(trackB)
+	if !ok {
+		t.Fatal("new key of a replaced order not answered")
+	}
+	if got, want := uids(orders), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("orders %v, want %v", got, want)
+	}
+	if c.list.Len() != 2 {
+		t.Errorf("list %d, want 2", c.list.Len())
+	}
+}
+
+func TestLRUCacheDelete(t *testing.T) {
+	now := time.Now()
+	c := New(10)
+	c.PutByKey(trackA, []*domain.Order{order("a", trackA.Value, now)})
+	c.Delete("a")
+	c.Delete("missing")
+
+	if c.Get("a") != nil {
+		t.Error("deleted order returned")
+	}
+	if _, ok := c.GetByKey(trackA); ok {
+		t.Error("key of a deleted order answered")
+	}
+
+	c.Put(order("a", trackA.Value, now))
+	c.PutByKey(trackA, []*domain.Order{order("a", trackA.Value, now)})
+	if c.Get("a") != nil {
+		t.Error("order put back right after deletion")
+	}
+
+	c.deleted["a"] = now.Add(-2 * deletedTTL)
+	c.Put(order("a", trackA.Value, now))
+	if c.Get("a") == nil {
+		t.Error("order kept out after deletedTTL")
+	}
+}
@@ -0,0 +1,74 @@
+package memoryorderbody
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	// Entry is valid while the order cache still returns Source.
+	Entry struct {
+		Source *domain.Order
+		Body   []byte
+		ETag   string
+	}
+
+	item struct {
+		key   string
+		entry *Entry
+	}
+
+	LRUCache struct {
+		capacity int
+		mx       sync.Mutex
+		data     map[string]*list.Element
+		list     *list.List
+	}
+)
+
+func New(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		data:     make(map[string]*list.Element),
+		list:     list.New(),
+	}
+}
+
+func (c *LRUCache) Get(key string) *Entry {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, inMap := c.data[key]; inMap {
+		c.list.MoveToFront(elem)
+		return elem.Value.(*item).entry
+	}
+
+	return nil
+}
+
+func (c *LRUCache) Put(key string, entry *Entry) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if elem, inMap := c.data[key]; inMap {
+		elem.Value.(*item).entry = entry
+		c.list.MoveToFront(elem)
+		return
+	}
+
+	c.data[key] = c.list.PushFront(&item{key: key, entry: entry})
+
+	if c.list.Len() > c.capacity {
+		last := c.list.Back()
+		if last != nil {
+			c.list.Remove(last)
+			delete(c.data, last.Value.(*item).key)
+		}
+	}
+}
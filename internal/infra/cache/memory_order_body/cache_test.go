@@ -0,0 +1,35 @@
+package memoryorderbody
+
+import "testing"
+
+func TestLRUCache(t *testing.T) {
+	c := New(2)
+	a, b, d := &Entry{ETag: `"a"`}, &Entry{ETag: `"b"`}, &Entry{ETag: `"d"`}
+	c.Put("a", a)
+	c.Put("b", b)
+	if c.Get("a") != a {
+		t.Fatal("a is missing")
+	}
+
+	c.Put("d", d)
+	if c.Get("b") != nil {
+		t.Error("least recently used b is kept")
+	}
+	if c.Get("a") != a || c.Get("d") != d {
+		t.Error("recently used entries are evicted")
+	}
+
+	replaced := &Entry{ETag: `"a2"`}
+	c.Put("a", replaced)
+	if c.Get("a") != replaced || c.list.Len() != 2 {
+		t.Errorf("replace: entry %v, %d entries", c.Get("a"), c.list.Len())
+	}
+}
+
+func TestLRUCacheDisabled(t *testing.T) {
+	c := New(0)
+	c.Put("a", &Entry{})
+	if c.Get("a") != nil {
+		t.Fatal("zero capacity cache stores entries")
+	}
+}
@@ -0,0 +1,163 @@
+// Package hub fans new orders out to live subscribers.
+package hub
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	Event struct {
+		ID    uint64
+		Order *domain.Order
+	}
+
+	Subscription struct {
+		hub    *Hub
+		events chan Event
+		match  func(order *domain.Order) bool
+		err    error
+	}
+
+	// Hub drops a subscriber whose buffer is full instead of blocking.
+	Hub struct {
+		mx          sync.Mutex
+		lastID      uint64
+		replay      []Event // ring buffer, oldest event at start
+		start       int
+		replaySize  int
+		bufferSize  int
+		subscribers map[*Subscription]struct{}
+		closed      bool
+	}
+)
+
+var (
+	ErrClosed         = errors.New("hub closed")
+	ErrSlowSubscriber = errors.New("subscriber too slow")
+)
+
+func New(replaySize, bufferSize int) *Hub {
+	return &Hub{
+		// IDs keep growing across restarts.
+		lastID:      uint64(time.Now().UnixMicro()),
+		replay:      make([]Event, 0, replaySize),
+		replaySize:  replaySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (h *Hub) Publish(order *domain.Order) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.lastID++
+	event := Event{
+		ID:    h.lastID,
+		Order: order,
+	}
+
+	if h.replaySize > 0 {
+		if len(h.replay) < h.replaySize {
+			h.replay = append(h.replay, event)
+		} else {
+			h.replay[h.start] = event
+			h.start = (h.start + 1) % h.replaySize
+		}
+	}
+
+	for sub := range h.subscribers {
+		if !sub.match(order) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub, ErrSlowSubscriber)
+		}
+	}
+}
+
+func (h *Hub) Forget(orderUIDs ...string) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	for i, event := range h.replay {
+		if event.Order != nil && slices.Contains(orderUIDs, event.Order.OrderUID) {
+			h.replay[i].Order = nil
+		}
+	}
+}
+
+// Subscribe replays buffered events with IDs greater than after; 0 skips the replay.
+func (h *Hub) Subscribe(after uint64, match func(order *domain.Order) bool) (*Subscription, []Event, error) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if h.closed {
+		return nil, nil, ErrClosed
+	}
+
+	var replay []Event
+	if after > 0 {
+		for i := range h.replay {
+			event := h.replay[(h.start+i)%len(h.replay)]
+			if event.ID > after && event.Order != nil && match(event.Order) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		hub:    h,
+		events: make(chan Event, h.bufferSize),
+		match:  match,
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub, replay, nil
+}
+
+func (h *Hub) Close() {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	h.closed = true
+	for sub := range h.subscribers {
+		h.remove(sub, ErrClosed)
+	}
+}
+
+func (h *Hub) remove(sub *Subscription, err error) {
+	if _, inMap := h.subscribers[sub]; !inMap {
+		return
+	}
+	delete(h.subscribers, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err is valid once Events is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.hub.mx.Lock()
+	defer s.hub.mx.Unlock()
+
+	s.hub.remove(s, nil)
+}
@@ -0,0 +1,194 @@
+package hub
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+func matchAll(*domain.Order) bool {
+	return true
+}
+
+func publish(h *Hub, orders []domain.Order) []uint64 {
+	ids := make([]uint64, 0, len(orders))
+	for i := range orders {
+		h.Publish(&orders[i])
+		ids = append(ids, h.lastID)
+	}
+	return ids
+}
+
+func eventIDs(events []Event) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestPublish(t *testing.T) {
+	h := New(0, 10)
+	orders := fixtures.New(1).Orders(3)
+	customer := "c-filtered"
+	orders[1].CustomerID = customer
+	all, _, err := h.Subscribe(0, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, _, err := h.Subscribe(0, domain.OrderFilter{CustomerID: customer}.Match)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := publish(h, orders)
+
+	for i, want := range ids {
+		event := <-all.Events()
+		if event.ID != want || event.Order.OrderUID != orders[i].OrderUID {
+			t.Errorf("event %d: %d %s, want %d %s", i, event.ID, event.Order.OrderUID, want, orders[i].OrderUID)
+		}
+	}
+	if len(filtered.Events()) != 1 {
+		t.Fatalf("filtered subscriber got %d events, want 1", len(filtered.Events()))
+	}
+	if event := <-filtered.Events(); event.Order.CustomerID != customer {
+		t.Errorf("filtered event of customer %s", event.Order.CustomerID)
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	h := New(3, 10)
+	orders := fixtures.New(2).Orders(5)
+	orders[3].CustomerID = "c-filtered"
+	ids := publish(h, orders)
+
+	tests := []struct {
+		name  string
+		after uint64
+		match func(*domain.Order) bool
+		want  []uint64
+	}{
+		{"no replay", 0, matchAll, nil},
+		{"buffer wrapped", ids[0], matchAll, ids[2:]},
+		{"partial", ids[3], matchAll, ids[4:]},
+		{"up to date", ids[4], matchAll, nil},
+		{"id from a previous process", 1, matchAll, ids[2:]},
+		{"filtered", ids[0], domain.OrderFilter{CustomerID: "c-filtered"}.Match, ids[3:4]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, replay, err := h.Subscribe(tt.after, tt.match)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub.Close()
+
+			if got := eventIDs(replay); !slices.Equal(got, tt.want) {
+				t.Errorf("replayed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	h := New(3, 10)
+	orders := fixtures.New(3).Orders(3)
+	ids := publish(h, orders)
+
+	h.Forget(orders[1].OrderUID)
+
+	sub, replay, err := h.Subscribe(1, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	if got, want := eventIDs(replay), []uint64{ids[0], ids[2]}; !slices.Equal(got, want) {
+		t.Errorf("replayed %v, want %v", got, want)
+	}
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	h := New(10, 1)
+	orders := fixtures.New(4).Orders(3)
+	slow, _, err := h.Subscribe(0, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := h.Subscribe(0, domain.OrderFilter{CustomerID: "nobody"}.Match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	ids := publish(h, orders)
+
+	event, open := <-slow.Events()
+	if !open || event.ID != ids[0] {
+		t.Fatalf("first event %d (open %v), want %d", event.ID, open, ids[0])
+	}
+	if _, open := <-slow.Events(); open {
+		t.Fatal("slow subscriber not dropped")
+	}
+	if !errors.Is(slow.Err(), ErrSlowSubscriber) {
+		t.Errorf("Err %v, want ErrSlowSubscriber", slow.Err())
+	}
+	if _, inMap := h.subscribers[other]; !inMap {
+		t.Error("a subscriber without matching events was dropped")
+	}
+
+	resumed, replay, err := h.Subscribe(event.ID, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Close()
+	if got := eventIDs(replay); !slices.Equal(got, ids[1:]) {
+		t.Errorf("replay after the drop %v, want %v", got, ids[1:])
+	}
+}
+
+func TestClose(t *testing.T) {
+	h := New(10, 10)
+	sub, _, err := h.Subscribe(0, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Close()
+
+	if _, open := <-sub.Events(); open {
+		t.Fatal("subscription open after Close")
+	}
+	if !errors.Is(sub.Err(), ErrClosed) {
+		t.Errorf("Err %v, want ErrClosed", sub.Err())
+	}
+	if _, _, err := h.Subscribe(0, matchAll); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe after Close: %v", err)
+	}
+	order := fixtures.New(5).Order()
+	h.Publish(&order)
+	sub.Close()
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	h := New(10, 10)
+	sub, _, err := h.Subscribe(0, matchAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub.Close()
+	sub.Close()
+
+	if _, open := <-sub.Events(); open {
+		t.Fatal("subscription open after Close")
+	}
+	if sub.Err() != nil {
+		t.Errorf("Err %v, want nil", sub.Err())
+	}
+	order := fixtures.New(6).Order()
+	h.Publish(&order)
+}
@@ -0,0 +1,56 @@
+package logctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.uber.org/zap"
+)
+
+const (
+	FieldRequestID = "request_id"
+	requestIDBytes = 16
+)
+
+type (
+	loggerKey    struct{}
+	requestIDKey struct{}
+)
+
+func With(ctx context.Context, logger *zap.Logger, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return context.WithValue(ctx, loggerKey{}, logger.With(zap.String(FieldRequestID, requestID)))
+}
+
+func Logger(ctx context.Context) (*zap.Logger, bool) {
+	logger, ok := ctx.Value(loggerKey{}).(*zap.Logger)
+	return logger, ok
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func NewRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID allows 1-128 characters of [A-Za-z0-9._:-].
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == ':', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
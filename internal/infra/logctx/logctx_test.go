@@ -0,0 +1,60 @@
+package logctx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"abc-123", true},
+		{"orders:0:42", true},
+		{"a.b_c", true},
+		{strings.Repeat("a", 128), true},
+		{"", false},
+		{strings.Repeat("a", 129), false},
+		{"with space", false},
+		{"line\nbreak", false},
+		{"юникод", false},
+	}
+	for _, tt := range tests {
+		if got := ValidRequestID(tt.id); got != tt.want {
+			t.Errorf("ValidRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	id := NewRequestID()
+	if len(id) != 2*requestIDBytes || !ValidRequestID(id) {
+		t.Fatalf("request ID %q", id)
+	}
+	if id == NewRequestID() {
+		t.Fatal("request IDs repeat")
+	}
+}
+
+func TestWith(t *testing.T) {
+	if _, ok := Logger(context.Background()); ok || RequestID(context.Background()) != "" {
+		t.Fatal("logger or request ID in an empty context")
+	}
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := With(context.Background(), zap.New(core), "req-1")
+
+	logger, ok := Logger(ctx)
+	if !ok || RequestID(ctx) != "req-1" {
+		t.Fatalf("logger %v, request ID %q", ok, RequestID(ctx))
+	}
+	logger.Info("hello")
+	if got := logs.All()[0].ContextMap()[FieldRequestID]; got != "req-1" {
+		t.Errorf("logged request ID %v", got)
+	}
+}
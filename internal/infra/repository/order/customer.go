@@ -0,0 +1,90 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+var allParts = domain.OrderParts{Delivery: true, Payment: true, Items: true}
+
+func (r *Repository) GetCustomerOrders(ctx context.Context, customerID string, limit, offset int,
+) ([]*domain.Order, error) {
+	const query = selectOrderRowsQuery + `
+	AND o.customer_id = $1
+	ORDER BY o.date_created DESC, o.order_uid DESC
+	LIMIT $2 OFFSET $3
+	`
+	orders, err := r.queryOrderRows(ctx, query, customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadParts(ctx, orders, allParts); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetCustomerSummary aggregates in one statement, so the figures agree.
+func (r *Repository) GetCustomerSummary(ctx context.Context, customerID string) (*domain.CustomerSummary, error) {
+	const query = `
+	WITH c AS (
+		SELECT o.date_created, d.city, p.currency, p.amount
+		FROM orders o
+		LEFT JOIN delivery d ON d.order_uid = o.order_uid
+		LEFT JOIN payment p ON p.order_uid = o.order_uid
+		WHERE o.customer_id = $1 AND o.deleted_at IS NULL
+	), spent AS (
+		SELECT currency, sum(amount)::bigint AS amount
+		FROM c
+		WHERE currency IS NOT NULL
+		GROUP BY currency
+	)
+	SELECT
+		(SELECT count(*) FROM c), (SELECT min(date_created) FROM c), (SELECT max(date_created) FROM c),
+		coalesce((
+			SELECT city FROM c
+			WHERE city IS NOT NULL
+			GROUP BY city
+			ORDER BY count(*) DESC, max(date_created) DESC, city
+			LIMIT 1
+		), ''),
+		coalesce((SELECT array_agg(currency ORDER BY currency) FROM spent), '{}'),
+		coalesce((SELECT array_agg(amount ORDER BY currency) FROM spent), '{}')
+	`
+	summary := &domain.CustomerSummary{CustomerID: customerID}
+	var first, last *time.Time
+	var currencies []string
+	var amounts []int64
+	err := r.conn.QueryRow(ctx, query, customerID).Scan(
+		&summary.Orders, &first, &last, &summary.TopCity, &currencies, &amounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Orders == 0 {
+		return nil, domain.ErrCustomerNotFound
+	}
+
+	fillCustomerSummary(summary, first, last, currencies, amounts)
+	return summary, nil
+}
+
+// fillCustomerSummary leaves the order dates zero when date_created is NULL
+// in every order.
+func fillCustomerSummary(summary *domain.CustomerSummary, first, last *time.Time, currencies []string,
+	amounts []int64,
+) {
+	if first != nil {
+		summary.FirstOrderAt = *first
+	}
+	if last != nil {
+		summary.LastOrderAt = *last
+	}
+	summary.Spent = make([]domain.CurrencyAmount, 0, len(currencies))
+	for i, currency := range currencies {
+		summary.Spent = append(summary.Spent, domain.CurrencyAmount{Currency: currency, Amount: amounts[i]})
+	}
+}
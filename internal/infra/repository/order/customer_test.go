@@ -0,0 +1,31 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func TestFillCustomerSummary(t *testing.T) {
+	first := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	summary := &domain.CustomerSummary{}
+	fillCustomerSummary(summary, &first, &last, []string{"RUB", "USD"}, []int64{1500, 20})
+	if !summary.FirstOrderAt.Equal(first) || !summary.LastOrderAt.Equal(last) {
+		t.Errorf("dates %v, %v", summary.FirstOrderAt, summary.LastOrderAt)
+	}
+	want := []domain.CurrencyAmount{{Currency: "RUB", Amount: 1500}, {Currency: "USD", Amount: 20}}
+	if len(summary.Spent) != len(want) || summary.Spent[0] != want[0] || summary.Spent[1] != want[1] {
+		t.Errorf("spent %+v, want %+v", summary.Spent, want)
+	}
+}
+
+func TestFillCustomerSummaryWithoutDates(t *testing.T) {
+	summary := &domain.CustomerSummary{}
+	fillCustomerSummary(summary, nil, nil, nil, nil)
+	if !summary.FirstOrderAt.IsZero() || !summary.LastOrderAt.IsZero() || summary.Spent == nil {
+		t.Errorf("summary %+v", summary)
+	}
+}
@@ -0,0 +1,143 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+// AnonymizeCustomer covers deleted orders too.
+func (r *Repository) AnonymizeCustomer(ctx context.Context, customerID, token string,
+	request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	const anonymizeOrders = `
+	UPDATE orders SET customer_id = $2, updated_at = now()
+	WHERE customer_id = $1
+	RETURNING order_uid
+	`
+	const anonymizeDeliveries = `
+	UPDATE delivery SET name = $2, phone = $3, zip = $4, address = $5, email = $6
+	WHERE order_uid = ANY($1)
+	`
+	erasure := &domain.Erasure{
+		Action:      domain.ErasureAnonymize,
+		Subject:     token,
+		RequestedBy: request.RequestedBy,
+		Reason:      request.Reason,
+		CustomerID:  customerID,
+	}
+	delivery := domain.AnonymizedDelivery(domain.Delivery{}, token)
+
+	err := r.InTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, anonymizeOrders, customerID, token)
+		if err != nil {
+			return err
+		}
+		erasure.OrderUIDs, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("anonymize orders: %w", err)
+		}
+		if len(erasure.OrderUIDs) == 0 {
+			return domain.ErrCustomerNotFound
+		}
+
+		_, err = tx.Exec(ctx, anonymizeDeliveries, erasure.OrderUIDs, delivery.Name, delivery.Phone, delivery.Zip,
+			delivery.Address, delivery.Email)
+		if err != nil {
+			return fmt.Errorf("anonymize deliveries: %w", err)
+		}
+
+		return insertErasure(ctx, tx, erasure)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return erasure, nil
+}
+
+func (r *Repository) SoftDeleteOrder(ctx context.Context, orderUID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	const query = `
+	UPDATE orders SET deleted_at = now()
+	WHERE order_uid = $1 AND deleted_at IS NULL
+	RETURNING coalesce(customer_id, '')
+	`
+	erasure := &domain.Erasure{
+		Action:      domain.ErasureDelete,
+		Subject:     orderUID,
+		RequestedBy: request.RequestedBy,
+		Reason:      request.Reason,
+		OrderUIDs:   []string{orderUID},
+	}
+
+	err := r.InTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, orderUID).Scan(&erasure.CustomerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrOrderNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return insertErasure(ctx, tx, erasure)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return erasure, nil
+}
+
+// PurgeDeletedOrders records nothing if there are no orders to purge.
+func (r *Repository) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	const query = `
+	DELETE FROM orders
+	WHERE deleted_at < $1
+	RETURNING order_uid
+	`
+	erasure := &domain.Erasure{
+		Action:      domain.ErasurePurge,
+		Subject:     "deleted before " + deletedBefore.UTC().Format(time.RFC3339),
+		RequestedBy: request.RequestedBy,
+		Reason:      request.Reason,
+	}
+
+	err := r.InTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, deletedBefore)
+		if err != nil {
+			return err
+		}
+		erasure.OrderUIDs, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("purge orders: %w", err)
+		}
+		if len(erasure.OrderUIDs) == 0 {
+			return nil
+		}
+
+		return insertErasure(ctx, tx, erasure)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return erasure, nil
+}
+
+func insertErasure(ctx context.Context, tx pgx.Tx, erasure *domain.Erasure) error {
+	const query = `
+	INSERT INTO erasure_audit (action, subject, requested_by, reason, orders)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, created_at
+	`
+	err := tx.QueryRow(ctx, query, string(erasure.Action), erasure.Subject, erasure.RequestedBy, erasure.Reason,
+		len(erasure.OrderUIDs)).Scan(&erasure.ID, &erasure.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert erasure audit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	exportCursorName = "orders_export"
+	exportFetchSize  = 500
+)
+
+func (r *Repository) ExportOrders(ctx context.Context, filter domain.OrderFilter,
+	fn func(order *domain.Order) error,
+) error {
+	where, args := filterConditions(filter)
+	query := selectOrdersQuery + where + `
+	ORDER BY o.date_created, o.order_uid
+	`
+
+	return r.InTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "DECLARE "+exportCursorName+" NO SCROLL CURSOR FOR "+query, args...)
+		if err != nil {
+			return fmt.Errorf("declare cursor: %w", err)
+		}
+
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", exportFetchSize, exportCursorName)
+		var current *domain.Order
+		for {
+			fetched, err := r.fetchOrderRows(ctx, tx, fetch, &current, fn)
+			if err != nil {
+				return err
+			}
+			if fetched == 0 {
+				break
+			}
+		}
+
+		if current != nil {
+			return fn(current)
+		}
+		return nil
+	})
+}
+
+// current carries an order whose rows continue into the next batch.
+func (r *Repository) fetchOrderRows(ctx context.Context, tx pgx.Tx, fetch string, current **domain.Order,
+	fn func(order *domain.Order) error,
+) (int, error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("fetch: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		order, item, err := scanOrderRow(rows)
+		if err != nil {
+			return 0, err
+		}
+
+		if *current != nil && (*current).OrderUID != order.OrderUID {
+			if err := fn(*current); err != nil {
+				return 0, err
+			}
+			*current = nil
+		}
+		if *current == nil {
+			*current = order
+		}
+		(*current).Items = append((*current).Items, item)
+	}
+
+	return fetched, rows.Err()
+}
+
+func filterConditions(filter domain.OrderFilter) (string, []any) {
+	conds := []string{}
+	args := []any{}
+
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.CustomerID != "" {
+		add("o.customer_id = $%d", filter.CustomerID)
+	}
+	if filter.DeliveryService != "" {
+		add("o.delivery_service = $%d", filter.DeliveryService)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("o.date_created >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("o.date_created < $%d", filter.CreatedTo)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return "\tAND " + strings.Join(conds, " AND ") + "\n", args
+}
@@ -0,0 +1,37 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+var keyConditions = map[domain.OrderKeyKind]string{
+	domain.KeyTrackNumber: `
+	AND (o.track_number = $1
+		OR o.order_uid IN (SELECT i.order_uid FROM items i WHERE i.track_number = $1))`,
+	domain.KeyTransaction: `
+	AND o.order_uid IN (SELECT p.order_uid FROM payment p WHERE p.transaction = $1)`,
+}
+
+func (r *Repository) GetOrdersByKey(ctx context.Context, key domain.OrderKey, limit int,
+) ([]*domain.Order, error) {
+	where, known := keyConditions[key.Kind]
+	if !known {
+		return nil, fmt.Errorf("unknown order key %q", key.Kind)
+	}
+	query := selectOrderRowsQuery + where + `
+	ORDER BY o.date_created DESC, o.order_uid DESC
+	LIMIT $2
+	`
+	orders, err := r.queryOrderRows(ctx, query, key.Value, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadParts(ctx, orders, allParts); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
@@ -0,0 +1,221 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const selectOrderRowsQuery = `
+	SELECT
+		o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
+		o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
+		coalesce(o.updated_at, o.date_created)
+	FROM orders o
+	WHERE o.deleted_at IS NULL
+`
+
+func (r *Repository) GetOrdersParts(ctx context.Context, orderUIDs []string, parts domain.OrderParts,
+) (map[string]*domain.Order, error) {
+	const query = selectOrderRowsQuery + `
+	AND o.order_uid = ANY($1)
+	`
+	orders, err := r.queryOrderRows(ctx, query, orderUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadParts(ctx, orders, parts); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*domain.Order, len(orders))
+	for _, order := range orders {
+		found[order.OrderUID] = order
+	}
+	return found, nil
+}
+
+func (r *Repository) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+	limit int, parts domain.OrderParts,
+) ([]*domain.Order, error) {
+	where, args := filterConditions(filter)
+	if after != nil {
+		args = append(args, after.DateCreated, after.OrderUID)
+		where += fmt.Sprintf("\tAND (o.date_created, o.order_uid) < ($%d, $%d)\n", len(args)-1, len(args))
+	}
+	args = append(args, limit)
+	query := selectOrderRowsQuery + where + fmt.Sprintf(`
+	ORDER BY o.date_created DESC, o.order_uid DESC
+	LIMIT $%d
+	`, len(args))
+
+	orders, err := r.queryOrderRows(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadParts(ctx, orders, parts); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *Repository) queryOrderRows(ctx context.Context, query string, args ...any) ([]*domain.Order, error) {
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Order, error) {
+		order := &domain.Order{}
+		err := row.Scan(
+			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale, &order.InternalSignature,
+			&order.CustomerID, &order.DeliveryService, &order.ShardKey, &order.SmID,
+			&order.DateCreated, &order.OofShard, &order.UpdatedAt,
+		)
+		return order, err
+	})
+}
+
+func (r *Repository) loadParts(ctx context.Context, orders []*domain.Order, parts domain.OrderParts) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byUID := make(map[string]*domain.Order, len(orders))
+	orderUIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byUID[order.OrderUID] = order
+		orderUIDs = append(orderUIDs, order.OrderUID)
+	}
+
+	if parts.Delivery {
+		if err := r.loadDeliveries(ctx, orderUIDs, byUID); err != nil {
+			return fmt.Errorf("load delivery: %w", err)
+		}
+	}
+	if parts.Payment {
+		if err := r.loadPayments(ctx, orderUIDs, byUID); err != nil {
+			return fmt.Errorf("load payment: %w", err)
+		}
+	}
+	if parts.Items {
+		for _, order := range orders {
+			order.Items = []domain.Item{}
+		}
+		if err := r.loadItems(ctx, orderUIDs, byUID); err != nil {
+			return fmt.Errorf("load items: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) loadDeliveries(ctx context.Context, orderUIDs []string, byUID map[string]*domain.Order) error {
+	const query = `
+	SELECT order_uid, name, phone, zip, city, address, region, email
+	FROM delivery
+	WHERE order_uid = ANY($1)
+	`
+	rows, err := r.conn.Query(ctx, query, orderUIDs)
+	if err != nil {
+		return err
+	}
+
+	var orderUID string
+	var d domain.Delivery
+	_, err = pgx.ForEachRow(rows, []any{&orderUID, &d.Name, &d.Phone, &d.Zip, &d.City, &d.Address,
+		&d.Region, &d.Email}, func() error {
+		byUID[orderUID].Delivery = d
+		return nil
+	})
+	return err
+}
+
+func (r *Repository) loadPayments(ctx context.Context, orderUIDs []string, byUID map[string]*domain.Order) error {
+	const query = `
+	SELECT order_uid, transaction, request_id, currency, provider, amount, payment_dt,
+	bank, delivery_cost, goods_total, custom_fee
+	FROM payment
+	WHERE order_uid = ANY($1)
+	`
+	rows, err := r.conn.Query(ctx, query, orderUIDs)
+	if err != nil {
+		return err
+	}
+
+	var orderUID string
+	var p domain.Payment
+	_, err = pgx.ForEachRow(rows, []any{&orderUID, &p.Transaction, &p.RequestID, &p.Currency, &p.Provider,
+		&p.Amount, &p.PaymentDT, &p.Bank, &p.DeliveryCost, &p.GoodsTotal, &p.CustomFee}, func() error {
+		byUID[orderUID].Payment = p
+		return nil
+	})
+	return err
+}
+
+func (r *Repository) loadItems(ctx context.Context, orderUIDs []string, byUID map[string]*domain.Order) error {
+	const query = `
+	SELECT order_uid, chrt_id, track_number, price, rid, name, sale,
+	size, total_price, nm_id, brand, status
+	FROM items
+	WHERE order_uid = ANY($1)
+	ORDER BY id
+	`
+	rows, err := r.conn.Query(ctx, query, orderUIDs)
+	if err != nil {
+		return err
+	}
+
+	var orderUID string
+	var it domain.Item
+	_, err = pgx.ForEachRow(rows, []any{&orderUID, &it.ChrtID, &it.TrackNumber, &it.Price, &it.RID, &it.Name,
+		&it.Sale, &it.Size, &it.TotalPrice, &it.NmID, &it.Brand, &it.Status}, func() error {
+		order := byUID[orderUID]
+		order.Items = append(order.Items, it)
+		return nil
+	})
+	return err
+}
+
+func (r *Repository) GetOrderItems(ctx context.Context, orderUID string, limit, offset int,
+) ([]domain.Item, int, error) {
+	const countQuery = `
+	SELECT count(*) FROM items WHERE order_uid = $1
+	`
+	const query = `
+	SELECT chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+	FROM items
+	WHERE order_uid = $1
+	ORDER BY id
+	LIMIT $2 OFFSET $3
+	`
+
+	var total int
+	if err := r.conn.QueryRow(ctx, countQuery, orderUID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count items: %w", err)
+	}
+	if offset >= total {
+		return []domain.Item{}, total, nil
+	}
+
+	rows, err := r.conn.Query(ctx, query, orderUID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Item, error) {
+		var it domain.Item
+		err := row.Scan(&it.ChrtID, &it.TrackNumber, &it.Price, &it.RID, &it.Name, &it.Sale, &it.Size,
+			&it.TotalPrice, &it.NmID, &it.Brand, &it.Status)
+		return it, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
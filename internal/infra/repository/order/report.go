@@ -0,0 +1,168 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const precomputedBucket = 15 * time.Minute
+
+const (
+	orderFacts = `
+	SELECT o.date_created AS created, coalesce(o.delivery_service, '') AS delivery_service,
+		o.customer_id, coalesce(p.provider, '') AS provider, coalesce(p.currency, '') AS currency,
+		1 AS orders, p.amount AS revenue,
+		(SELECT count(*) FROM items i WHERE i.order_uid = o.order_uid) AS items
+	FROM orders o
+	INNER JOIN payment p ON p.order_uid = o.order_uid
+	WHERE o.deleted_at IS NULL`
+	orderFactsView = `
+	SELECT bucket AS created, delivery_service, NULL::text AS customer_id, provider, currency,
+		orders, revenue, items
+	FROM report_order_facts`
+
+	brandFacts = `
+	SELECT o.date_created AS created, coalesce(o.delivery_service, '') AS delivery_service,
+		o.customer_id, coalesce(i.brand, '') AS brand, 1 AS items
+	FROM orders o
+	INNER JOIN items i ON i.order_uid = o.order_uid
+	WHERE o.deleted_at IS NULL`
+	brandFactsView = `
+	SELECT bucket AS created, delivery_service, NULL::text AS customer_id, brand, items
+	FROM report_brand_facts`
+)
+
+var refreshReportsQueries = []string{
+	"REFRESH MATERIALIZED VIEW CONCURRENTLY report_order_facts",
+	"REFRESH MATERIALIZED VIEW CONCURRENTLY report_brand_facts",
+}
+
+func (r *Repository) Report(ctx context.Context, kind domain.ReportKind, query domain.ReportQuery,
+) (*domain.Report, error) {
+	precomputed := fitsPrecomputed(query)
+	source := orderFacts
+	if precomputed {
+		source = orderFactsView
+	}
+
+	var args []any
+	var sql string
+	switch kind {
+	case domain.ReportDaily:
+		args = append(args, query.Location.String())
+		sql = `
+	SELECT to_char(f.created AT TIME ZONE $1, 'YYYY-MM-DD') AS day, f.currency,
+		sum(f.orders)::bigint AS orders, sum(f.revenue)::bigint AS revenue
+	FROM (%s) f%s
+	GROUP BY 1, 2
+	ORDER BY 1, 2`
+	case domain.ReportDeliveryServices:
+		sql = groupedRevenue("delivery_service")
+	case domain.ReportProviders:
+		sql = groupedRevenue("provider")
+	case domain.ReportCurrencies:
+		sql = `
+	SELECT f.currency, sum(f.orders)::bigint AS orders, sum(f.revenue)::bigint AS revenue
+	FROM (%s) f%s
+	GROUP BY 1
+	ORDER BY 1`
+	case domain.ReportTopBrands:
+		source = brandFacts
+		if precomputed {
+			source = brandFactsView
+		}
+		sql = `
+	SELECT f.brand, sum(f.items)::bigint AS items
+	FROM (%s) f%s
+	GROUP BY 1
+	ORDER BY 2 DESC, 1`
+	case domain.ReportItemsPerOrder:
+		sql = `
+	SELECT coalesce(sum(f.orders), 0)::bigint AS orders, coalesce(sum(f.items), 0)::bigint AS items,
+		coalesce(round(sum(f.items)::numeric / nullif(sum(f.orders), 0), 2), 0)::float8 AS items_per_order
+	FROM (%s) f%s`
+	default:
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownReport, kind)
+	}
+
+	where, args := factConditions(query.Filter, args)
+	sql = fmt.Sprintf(sql, source, where)
+	if kind == domain.ReportTopBrands {
+		args = append(args, query.Limit)
+		sql += fmt.Sprintf("\n\tLIMIT $%d", len(args))
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &domain.Report{Rows: [][]any{}}
+	for _, field := range rows.FieldDescriptions() {
+		report.Columns = append(report.Columns, field.Name)
+	}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		report.Rows = append(report.Rows, values)
+	}
+	return report, rows.Err()
+}
+
+func (r *Repository) RefreshReports(ctx context.Context) error {
+	for _, query := range refreshReportsQueries {
+		if _, err := r.conn.Exec(ctx, query); err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+	}
+	return nil
+}
+
+func groupedRevenue(column string) string {
+	return `
+	SELECT f.` + column + `, f.currency, sum(f.orders)::bigint AS orders, sum(f.revenue)::bigint AS revenue
+	FROM (%s) f%s
+	GROUP BY 1, 2
+	ORDER BY 1, 2`
+}
+
+// fitsPrecomputed: the views keep no customers and start at whole buckets.
+func fitsPrecomputed(query domain.ReportQuery) bool {
+	filter := query.Filter
+	return query.Precomputed && filter.CustomerID == "" &&
+		filter.CreatedFrom.Equal(filter.CreatedFrom.Truncate(precomputedBucket)) &&
+		filter.CreatedTo.Equal(filter.CreatedTo.Truncate(precomputedBucket))
+}
+
+func factConditions(filter domain.OrderFilter, args []any) (string, []any) {
+	conds := []string{}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.CustomerID != "" {
+		add("f.customer_id = $%d", filter.CustomerID)
+	}
+	if filter.DeliveryService != "" {
+		add("f.delivery_service = $%d", filter.DeliveryService)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("f.created >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("f.created < $%d", filter.CreatedTo)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return "\n\tWHERE " + strings.Join(conds, " AND "), args
+}
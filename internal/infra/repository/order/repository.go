@@ -9,10 +9,32 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
 )
 
+const selectOrdersQuery = `
+	SELECT
+		o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
+		o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
+		coalesce(o.updated_at, o.date_created),
+
+		d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+
+		p.transaction, p.request_id, p.currency, p.provider, p.amount, p.payment_dt,
+		p.bank, p.delivery_cost, p.goods_total, p.custom_fee,
+
+		i.chrt_id, i.track_number, i.price, i.rid, i.name, i.sale,
+		i.size, i.total_price, i.nm_id, i.brand, i.status
+	FROM orders o
+	INNER JOIN delivery d ON o.order_uid = d.order_uid
+	INNER JOIN payment p ON o.order_uid = p.order_uid
+	LEFT JOIN items i ON o.order_uid = i.order_uid
+	WHERE o.deleted_at IS NULL
+`
+
 type Repository struct {
 	conn *pgxpool.Pool
 }
@@ -31,6 +53,10 @@ func (r *Repository) InTx(ctx context.Context, f func(tx pgx.Tx) error) error {
 	defer func(tx pgx.Tx, ctx context.Context) {
 		err := tx.Rollback(ctx)
 		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			if logger, ok := logctx.Logger(ctx); ok {
+				logger.Error("tx.Rollback", zap.Error(err))
+				return
+			}
 			log.Printf("tx.Rollback: %v", err)
 		}
 	}(tx, ctx)
@@ -44,56 +70,87 @@ func (r *Repository) InTx(ctx context.Context, f func(tx pgx.Tx) error) error {
 }
 
 func (r *Repository) AddOrder(ctx context.Context, order domain.Order) error {
-	err := r.InTx(ctx, func(tx pgx.Tx) error {
-		var err error
-
-		err = r.addOrder(ctx, order)
+	return r.InTx(ctx, func(tx pgx.Tx) error {
+		inserted, err := r.addFullOrder(ctx, tx, order)
 		if err != nil {
 			return err
 		}
-
-		err = r.addDelivery(ctx, order.OrderUID, order.Delivery)
-		if err != nil {
-			return err
-		}
-
-		err = r.addPayment(ctx, order.OrderUID, order.Payment)
-		if err != nil {
-			return err
+		if !inserted {
+			return fmt.Errorf("%w: %s", domain.ErrOrderAlreadyExists, order.OrderUID)
 		}
+		return nil
+	})
+}
 
-		err = r.addItems(ctx, order.OrderUID, order.Items)
-		if err != nil {
-			return err
+// AddOrders returns the UIDs that already existed.
+func (r *Repository) AddOrders(ctx context.Context, orders []domain.Order) ([]string, error) {
+	duplicates := []string{}
+	err := r.InTx(ctx, func(tx pgx.Tx) error {
+		duplicates = duplicates[:0]
+		for _, order := range orders {
+			inserted, err := r.addFullOrder(ctx, tx, order)
+			if err != nil {
+				return fmt.Errorf("order %s: %w", order.OrderUID, err)
+			}
+			if !inserted {
+				duplicates = append(duplicates, order.OrderUID)
+			}
 		}
-
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+func (r *Repository) addFullOrder(ctx context.Context, tx pgx.Tx, order domain.Order) (bool, error) {
+	inserted, err := r.addOrder(ctx, tx, order)
+	if err != nil || !inserted {
+		return false, err
+	}
+
+	err = r.addDelivery(ctx, tx, order.OrderUID, order.Delivery)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.addPayment(ctx, tx, order.OrderUID, order.Payment)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.addItems(ctx, tx, order.OrderUID, order.Items)
+	if err != nil {
+		return false, err
+	}
 
-	return err
+	return true, nil
 }
 
-func (r *Repository) addOrder(ctx context.Context, order domain.Order) error {
+func (r *Repository) addOrder(ctx context.Context, tx pgx.Tx, order domain.Order) (bool, error) {
 	const query = `
 	INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id,
 	delivery_service, shardkey, sm_id, date_created, oof_shard)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (order_uid) DO NOTHING
 	`
-	_, err := r.conn.Exec(ctx, query, order.OrderUID, order.TrackNumber, order.Entry, order.Locale,
+	tag, err := tx.Exec(ctx, query, order.OrderUID, order.TrackNumber, order.Entry, order.Locale,
 		order.InternalSignature, order.CustomerID, order.DeliveryService, order.ShardKey, order.SmID,
 		order.DateCreated, order.OofShard)
 	if err != nil {
-		return err
+		return false, err
 	}
-	return nil
+	return tag.RowsAffected() == 1, nil
 }
 
-func (r *Repository) addDelivery(ctx context.Context, orderUUID string, delivery domain.Delivery) error {
+func (r *Repository) addDelivery(ctx context.Context, tx pgx.Tx, orderUUID string, delivery domain.Delivery) error {
 	const query = `
 	INSERT INTO delivery (order_uid, name, phone, zip, city, address, region, email)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err := r.conn.Exec(ctx, query, orderUUID, delivery.Name, delivery.Phone, delivery.Zip,
+	_, err := tx.Exec(ctx, query, orderUUID, delivery.Name, delivery.Phone, delivery.Zip,
 		delivery.City, delivery.Address, delivery.Region, delivery.Email)
 	if err != nil {
 		return err
@@ -101,13 +158,13 @@ func (r *Repository) addDelivery(ctx context.Context, orderUUID string, delivery
 	return nil
 }
 
-func (r *Repository) addPayment(ctx context.Context, orderUUID string, payment domain.Payment) error {
+func (r *Repository) addPayment(ctx context.Context, tx pgx.Tx, orderUUID string, payment domain.Payment) error {
 	const query = `
 	INSERT INTO payment (order_uid, transaction, request_id, currency, provider, amount, payment_dt,
 	bank, delivery_cost, goods_total, custom_fee)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.conn.Exec(ctx, query, orderUUID, payment.Transaction, payment.RequestID,
+	_, err := tx.Exec(ctx, query, orderUUID, payment.Transaction, payment.RequestID,
 		payment.Currency, payment.Provider, payment.Amount, payment.PaymentDT, payment.Bank,
 		payment.DeliveryCost, payment.GoodsTotal, payment.CustomFee)
 	if err != nil {
@@ -116,7 +173,7 @@ func (r *Repository) addPayment(ctx context.Context, orderUUID string, payment d
 	return nil
 }
 
-func (r *Repository) addItems(ctx context.Context, orderUUID string, items []domain.Item) error {
+func (r *Repository) addItems(ctx context.Context, tx pgx.Tx, orderUUID string, items []domain.Item) error {
 	vals := []any{}
 	placeholders := []string{}
 	for i, it := range items {
@@ -133,7 +190,7 @@ func (r *Repository) addItems(ctx context.Context, orderUUID string, items []dom
 	INSERT INTO items (order_uid, chrt_id, track_number, price, rid, name, sale,
 	size, total_price, nm_id, brand, status) VALUES ` + strings.Join(placeholders, ",")
 
-	_, err := r.conn.Exec(ctx, query, vals...)
+	_, err := tx.Exec(ctx, query, vals...)
 	if err != nil {
 		return err
 	}
@@ -141,22 +198,7 @@ func (r *Repository) addItems(ctx context.Context, orderUUID string, items []dom
 }
 
 func (r *Repository) GetOrders(ctx context.Context, amount int64) ([]*domain.Order, error) {
-	const query = `
-	SELECT
-		o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
-		o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
-
-		d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
-
-		p.transaction, p.request_id, p.currency, p.provider, p.amount, p.payment_dt,
-		p.bank, p.delivery_cost, p.goods_total, p.custom_fee,
-
-		i.chrt_id, i.track_number, i.price, i.rid, i.name, i.sale,
-		i.size, i.total_price, i.nm_id, i.brand, i.status
-	FROM orders o
-	INNER JOIN delivery d ON o.order_uid = d.order_uid
-	INNER JOIN payment p ON o.order_uid = p.order_uid
-	LEFT JOIN items i ON o.order_uid = i.order_uid
+	const query = selectOrdersQuery + `
 	ORDER BY o.date_created DESC
 	LIMIT $1
 	`
@@ -180,23 +222,8 @@ func (r *Repository) GetOrders(ctx context.Context, amount int64) ([]*domain.Ord
 }
 
 func (r *Repository) GetOrder(ctx context.Context, orderUID string) (*domain.Order, error) {
-	const query = `
-	SELECT
-		o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
-		o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
-
-		d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
-
-		p.transaction, p.request_id, p.currency, p.provider, p.amount, p.payment_dt,
-		p.bank, p.delivery_cost, p.goods_total, p.custom_fee,
-
-		i.chrt_id, i.track_number, i.price, i.rid, i.name, i.sale,
-		i.size, i.total_price, i.nm_id, i.brand, i.status
-	FROM orders o
-	INNER JOIN delivery d ON o.order_uid = d.order_uid
-	INNER JOIN payment p ON o.order_uid = p.order_uid
-	LEFT JOIN items i ON o.order_uid = i.order_uid
-	WHERE o.order_uid = $1
+	const query = selectOrdersQuery + `
+	AND o.order_uid = $1
 	`
 	rows, err := r.conn.Query(ctx, query, orderUID)
 	if err != nil {
@@ -219,38 +246,32 @@ func (r *Repository) GetOrder(ctx context.Context, orderUID string) (*domain.Ord
 	return orderResult, nil
 }
 
+func (r *Repository) GetOrdersByUIDs(ctx context.Context, orderUIDs []string) (map[string]*domain.Order, error) {
+	const query = selectOrdersQuery + `
+	AND o.order_uid = ANY($1)
+	`
+	rows, err := r.conn.Query(ctx, query, orderUIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrderRows(rows)
+}
+
 func scanOrderRows(rows pgx.Rows) (map[string]*domain.Order, error) {
 	ordersMap := make(map[string]*domain.Order)
 
 	for rows.Next() {
-		var order domain.Order
-		var delivery domain.Delivery
-		var payment domain.Payment
-		var item domain.Item
-
-		err := rows.Scan(
-			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale, &order.InternalSignature,
-			&order.CustomerID, &order.DeliveryService, &order.ShardKey, &order.SmID,
-			&order.DateCreated, &order.OofShard,
-			&delivery.Name, &delivery.Phone, &delivery.Zip, &delivery.City, &delivery.Address,
-			&delivery.Region, &delivery.Email,
-			&payment.Transaction, &payment.RequestID, &payment.Currency, &payment.Provider,
-			&payment.Amount, &payment.PaymentDT, &payment.Bank, &payment.DeliveryCost,
-			&payment.GoodsTotal, &payment.CustomFee,
-			&item.ChrtID, &item.TrackNumber, &item.Price, &item.RID, &item.Name, &item.Sale,
-			&item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
-		)
+		order, item, err := scanOrderRow(rows)
 		if err != nil {
 			return nil, err
 		}
 
 		o, inMap := ordersMap[order.OrderUID]
 		if !inMap {
-			order.Delivery = delivery
-			order.Payment = payment
-			order.Items = []domain.Item{}
-			ordersMap[order.OrderUID] = &order
-			o = &order
+			ordersMap[order.OrderUID] = order
+			o = order
 		}
 
 		o.Items = append(o.Items, item)
@@ -258,3 +279,32 @@ func scanOrderRows(rows pgx.Rows) (map[string]*domain.Order, error) {
 
 	return ordersMap, nil
 }
+
+func scanOrderRow(rows pgx.Rows) (*domain.Order, domain.Item, error) {
+	var order domain.Order
+	var delivery domain.Delivery
+	var payment domain.Payment
+	var item domain.Item
+
+	err := rows.Scan(
+		&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale, &order.InternalSignature,
+		&order.CustomerID, &order.DeliveryService, &order.ShardKey, &order.SmID,
+		&order.DateCreated, &order.OofShard, &order.UpdatedAt,
+		&delivery.Name, &delivery.Phone, &delivery.Zip, &delivery.City, &delivery.Address,
+		&delivery.Region, &delivery.Email,
+		&payment.Transaction, &payment.RequestID, &payment.Currency, &payment.Provider,
+		&payment.Amount, &payment.PaymentDT, &payment.Bank, &payment.DeliveryCost,
+		&payment.GoodsTotal, &payment.CustomFee,
+		&item.ChrtID, &item.TrackNumber, &item.Price, &item.RID, &item.Name, &item.Sale,
+		&item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
+	)
+	if err != nil {
+		return nil, domain.Item{}, err
+	}
+
+	order.Delivery = delivery
+	order.Payment = payment
+	order.Items = []domain.Item{}
+
+	return &order, item, nil
+}
@@ -0,0 +1,97 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+// The search expressions must stay identical to the indexed ones.
+const (
+	deliveryDocument = `to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, '') || ' ' ||
+		coalesce(phone, '') || ' ' || coalesce(city, '') || ' ' || coalesce(address, ''))`
+	deliveryPublicDocument = `to_tsvector('simple', coalesce(city, ''))`
+	itemDocument           = `to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(brand, ''))`
+	phoneDigits            = `regexp_replace(phone, '\D', '', 'g')`
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchOrders requires every word to occur in the delivery or in one item.
+func (r *Repository) SearchOrders(ctx context.Context, search domain.OrderSearch) ([]domain.OrderHit, error) {
+	delivery := deliveryPublicDocument
+	if search.PII {
+		delivery = deliveryDocument
+	}
+
+	args := []any{search.Text}
+	matches := []string{
+		`SELECT order_uid, ts_rank(` + delivery + `, q) AS rank
+		FROM delivery, websearch_to_tsquery('simple', $1) q
+		WHERE ` + delivery + ` @@ q`,
+		`SELECT order_uid, ts_rank(` + itemDocument + `, q) AS rank
+		FROM items, websearch_to_tsquery('simple', $1) q
+		WHERE ` + itemDocument + ` @@ q`,
+	}
+	if digits := search.PhoneDigits(); digits != "" && search.PII {
+		args = append(args, digits)
+		matches = append(matches, fmt.Sprintf(`SELECT order_uid, similarity(%[1]s, $%[2]d) AS rank
+		FROM delivery
+		WHERE %[1]s LIKE '%%' || $%[2]d || '%%'`, phoneDigits, len(args)))
+	}
+	if token := search.Token(); token != "" {
+		args = append(args, token, "%"+likeEscaper.Replace(token)+"%")
+		matches = append(matches, fmt.Sprintf(`SELECT order_uid, similarity(track_number, $%d) AS rank
+		FROM orders
+		WHERE track_number ILIKE $%d`, len(args)-1, len(args)))
+	}
+
+	args = append(args, search.Limit, search.Offset)
+	query := fmt.Sprintf(`
+	SELECT order_uid, sum(rank)::float8 AS rank
+	FROM (%s) matches
+	INNER JOIN orders o USING (order_uid)
+	WHERE o.deleted_at IS NULL
+	GROUP BY order_uid
+	ORDER BY rank DESC, order_uid
+	LIMIT $%d OFFSET $%d
+	`, strings.Join(matches, "\n\t\tUNION ALL\n\t\t"), len(args)-1, len(args))
+
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.OrderHit, error) {
+		hit := domain.OrderHit{Order: &domain.Order{}}
+		err := row.Scan(&hit.Order.OrderUID, &hit.Rank)
+		return hit, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rank orders: %w", err)
+	}
+	if len(hits) == 0 {
+		return hits, nil
+	}
+
+	orderUIDs := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		orderUIDs = append(orderUIDs, hit.Order.OrderUID)
+	}
+	orders, err := r.GetOrdersByUIDs(ctx, orderUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get orders: %w", err)
+	}
+
+	found := hits[:0]
+	for _, hit := range hits {
+		if order, inMap := orders[hit.Order.OrderUID]; inMap {
+			hit.Order = order
+			found = append(found, hit)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,35 @@
+package order
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearchExpressionsIndexed(t *testing.T) {
+	files, err := filepath.Glob("../../../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("migrations %v: %v", files, err)
+	}
+	var migrations strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		migrations.Write(data)
+	}
+	indexed := strings.Join(strings.Fields(migrations.String()), " ")
+
+	for name, expression := range map[string]string{
+		"deliveryDocument":       deliveryDocument,
+		"deliveryPublicDocument": deliveryPublicDocument,
+		"itemDocument":           itemDocument,
+		"phoneDigits":            phoneDigits,
+	} {
+		if !strings.Contains(indexed, "GIN ( "+strings.Join(strings.Fields(expression), " ")) {
+			t.Errorf("no GIN index on %s", name)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/AndrejDubinin/wbtech-l0/internal/app/consumer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
 )
 
 type (
@@ -16,12 +17,19 @@ type (
 	}
 )
 
+func ctxLogger(ctx context.Context, fallback logger) logger {
+	if l, ok := logctx.Logger(ctx); ok {
+		return l
+	}
+	return fallback
+}
+
 func Panic(next *consumer.Handler, logger logger) *consumer.Handler {
 	return &consumer.Handler{
 		ServeMsgFn: func(ctx context.Context, msg *sarama.ConsumerMessage) {
 			defer func() {
 				if r := recover(); r != nil {
-					logger.Error("panic recovered in consumer",
+					ctxLogger(ctx, logger).Error("panic recovered in consumer",
 						zap.Any("error", r),
 						zap.String("topic", msg.Topic),
 						zap.Int32("partition", msg.Partition),
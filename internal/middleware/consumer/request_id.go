@@ -0,0 +1,40 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/consumer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+type baseLogger interface {
+	With(fields ...zap.Field) *zap.Logger
+}
+
+func RequestID(next *consumer.Handler, logger baseLogger) *consumer.Handler {
+	return &consumer.Handler{
+		ServeMsgFn: func(ctx context.Context, msg *sarama.ConsumerMessage) {
+			msgLogger := logger.With(
+				zap.String("topic", msg.Topic),
+				zap.Int32("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset))
+			next.ServeMsgFn(logctx.With(ctx, msgLogger, messageRequestID(msg)), msg)
+		},
+	}
+}
+
+func messageRequestID(msg *sarama.ConsumerMessage) string {
+	for _, header := range msg.Headers {
+		if header != nil && string(header.Key) == definitions.HeaderRequestID {
+			if id := string(header.Value); logctx.ValidRequestID(id) {
+				return id
+			}
+		}
+	}
+	return fmt.Sprintf("%s:%d:%d", msg.Topic, msg.Partition, msg.Offset)
+}
@@ -0,0 +1,51 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/consumer"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(&consumer.Handler{ServeMsgFn: func(ctx context.Context, _ *sarama.ConsumerMessage) {
+		seen = logctx.RequestID(ctx)
+	}}, zap.NewNop())
+
+	header := func(value string) []*sarama.RecordHeader {
+		return []*sarama.RecordHeader{nil, {Key: []byte(definitions.HeaderRequestID), Value: []byte(value)}}
+	}
+	tests := []struct {
+		name    string
+		headers []*sarama.RecordHeader
+		want    string
+	}{
+		{"header", header("req-1"), "req-1"},
+		{"invalid header", header("bad id"), "orders:2:42"},
+		{"no header", nil, "orders:2:42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.ServeMsgFn(context.Background(), &sarama.ConsumerMessage{
+				Topic: "orders", Partition: 2, Offset: 42, Headers: tt.headers,
+			})
+			if seen != tt.want {
+				t.Errorf("request ID %q, want %q", seen, tt.want)
+			}
+		})
+	}
+}
+
+func TestPanic(t *testing.T) {
+	h := Panic(&consumer.Handler{ServeMsgFn: func(context.Context, *sarama.ConsumerMessage) {
+		panic("boom")
+	}}, zap.NewNop())
+
+	h.ServeMsgFn(context.Background(), &sarama.ConsumerMessage{Topic: "orders"})
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+)
+
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+)
+
+type authenticator interface {
+	AuthenticateCredentials(key, authorization string) (auth.Principal, error)
+}
+
+// AuthUnaryInterceptor leaves methods missing from scopes open.
+func AuthUnaryInterceptor(authenticator authenticator, scopes map[string]string, logger logger,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authenticator, scopes, info.FullMethod, logger)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptor(authenticator authenticator, scopes map[string]string, logger logger,
+) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), authenticator, scopes, info.FullMethod, logger)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, authenticator authenticator, scopes map[string]string, method string,
+	logger logger,
+) (context.Context, error) {
+	scope, protected := scopes[method]
+	if !protected {
+		return ctx, nil
+	}
+
+	principal, err := authenticator.AuthenticateCredentials(firstMetadata(ctx, apiKeyMetadata),
+		firstMetadata(ctx, authorizationMetadata))
+	if err != nil {
+		ctxLogger(ctx, logger).Info("authentication failed", zap.String("method", method), zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	if !principal.HasScope(scope) {
+		ctxLogger(ctx, logger).Info("access denied", zap.String("method", method),
+			zap.String("subject", principal.Subject), zap.String("scope", scope))
+		return nil, status.Errorf(codes.PermissionDenied, "scope %q required", scope)
+	}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
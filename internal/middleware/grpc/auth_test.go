@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+)
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) AuthenticateCredentials(key, authorization string) (auth.Principal, error) {
+	switch {
+	case key == "reader":
+		return auth.Principal{Subject: "reader", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}}, nil
+	case authorization == "Bearer writer":
+		return auth.Principal{Subject: "writer", Method: "jwt", Scopes: []string{auth.ScopeOrdersWrite}}, nil
+	}
+	return auth.Principal{}, errors.New("unknown credentials")
+}
+
+var testScopes = map[string]string{"/order.v1.OrderService/GetOrder": auth.ScopeOrdersRead}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	interceptor := AuthUnaryInterceptor(fakeAuthenticator{}, testScopes, zap.NewNop())
+
+	tests := []struct {
+		name, method string
+		md           metadata.MD
+		code         codes.Code
+		subject      string
+	}{
+		{"authorized", "/order.v1.OrderService/GetOrder", metadata.Pairs(apiKeyMetadata, " reader "), codes.OK, "reader"},
+		{"no credentials", "/order.v1.OrderService/GetOrder", nil, codes.Unauthenticated, ""},
+		{"missing scope", "/order.v1.OrderService/GetOrder", metadata.Pairs(authorizationMetadata, "Bearer writer"),
+			codes.PermissionDenied, ""},
+		{"open method", "/grpc.health.v1.Health/Check", nil, codes.OK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			var subject string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, _ any) (any, error) {
+					principal, _ := auth.PrincipalFromContext(ctx)
+					subject = principal.Subject
+					return nil, nil
+				})
+			if status.Code(err) != tt.code {
+				t.Fatalf("err %v, want %s", err, tt.code)
+			}
+			if subject != tt.subject {
+				t.Errorf("principal %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	interceptor := AuthStreamInterceptor(fakeAuthenticator{}, testScopes, zap.NewNop())
+	info := &grpc.StreamServerInfo{FullMethod: "/order.v1.OrderService/GetOrder", IsServerStream: true}
+
+	var subject string
+	handler := func(_ any, ss grpc.ServerStream) error {
+		principal, _ := auth.PrincipalFromContext(ss.Context())
+		subject = principal.Subject
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyMetadata, "reader"))
+	if err := interceptor(nil, &fakeStream{ctx: ctx}, info, handler); err != nil || subject != "reader" {
+		t.Fatalf("err %v, principal %q", err, subject)
+	}
+
+	err := interceptor(nil, &fakeStream{ctx: context.Background()}, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("err %v, want Unauthenticated", err)
+	}
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"expvar"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
+)
+
+const (
+	retryAfterMetadata   = "retry-after"
+	rejectionLogInterval = 10 * time.Second
+)
+
+var rejections = expvar.NewMap("grpc_rejections")
+
+// RateLimitUnaryInterceptor must run after AuthUnaryInterceptor.
+func RateLimitUnaryInterceptor(limiter *ratelimit.Limiter, logger logger) grpc.UnaryServerInterceptor {
+	logSometimes := &rate.Sometimes{Interval: rejectionLogInterval}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if header, err := reserve(ctx, limiter, info.FullMethod, logSometimes, logger); err != nil {
+			_ = grpc.SetHeader(ctx, header)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func RateLimitStreamInterceptor(limiter *ratelimit.Limiter, logger logger) grpc.StreamServerInterceptor {
+	logSometimes := &rate.Sometimes{Interval: rejectionLogInterval}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if header, err := reserve(ss.Context(), limiter, info.FullMethod, logSometimes, logger); err != nil {
+			_ = ss.SetHeader(header)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func reserve(ctx context.Context, limiter *ratelimit.Limiter, method string, logSometimes *rate.Sometimes,
+	logger logger,
+) (metadata.MD, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	key := ratelimit.Key(principal, ok, peerIP(ctx))
+	delay := limiter.Reserve(key)
+	if delay <= 0 {
+		return nil, nil
+	}
+
+	rejections.Add("rate_limited", 1)
+	logSometimes.Do(func() {
+		ctxLogger(ctx, logger).Warn("rate limit exceeded", zap.String("client", key), zap.String("method", method))
+	})
+
+	retryAfter := strconv.Itoa(int(math.Ceil(delay.Seconds())))
+	return metadata.Pairs(retryAfterMetadata, retryAfter), status.Error(codes.ResourceExhausted, "too many requests")
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
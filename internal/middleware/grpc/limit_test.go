@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
+)
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5000}})
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	interceptor := RateLimitUnaryInterceptor(ratelimit.New(1, 1, 100), zap.NewNop())
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"}
+	call := func(ctx context.Context) error {
+		_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) { return nil, nil })
+		return err
+	}
+	principal := auth.Principal{Subject: "support", Method: "api_key", Scopes: []string{auth.ScopeOrdersRead}}
+
+	if err := call(auth.WithPrincipal(peerContext("203.0.113.7"), principal)); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	err := call(auth.WithPrincipal(peerContext("203.0.113.8"), principal))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("err %v, want the principal limited across IPs", err)
+	}
+
+	if err := call(peerContext("203.0.113.7")); err != nil {
+		t.Fatalf("err %v, want the IP limited apart from the principal", err)
+	}
+	if err := call(auth.WithPrincipal(peerContext("203.0.113.7"), auth.Anonymous)); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("err %v, want anonymous calls keyed by IP", err)
+	}
+	if err := call(peerContext("203.0.113.9")); err != nil {
+		t.Fatalf("err %v, want other IPs unaffected", err)
+	}
+}
+
+func TestRateLimitStreamInterceptor(t *testing.T) {
+	interceptor := RateLimitStreamInterceptor(ratelimit.New(1, 1, 100), zap.NewNop())
+	info := &grpc.StreamServerInfo{FullMethod: "/order.v1.OrderService/ListOrders", IsServerStream: true}
+	var handled int
+	handler := func(any, grpc.ServerStream) error {
+		handled++
+		return nil
+	}
+
+	if err := interceptor(nil, &fakeStream{ctx: peerContext("203.0.113.7")}, info, handler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	ss := &fakeStream{ctx: peerContext("203.0.113.7")}
+	err := interceptor(nil, ss, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("err %v, want ResourceExhausted", err)
+	}
+	if handled != 1 {
+		t.Errorf("handler ran %d times, want 1", handled)
+	}
+	if got := ss.header.Get(retryAfterMetadata); len(got) != 1 || got[0] != "1" {
+		t.Errorf("retry-after %v", got)
+	}
+}
+
+func TestPeerIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"ipv4", peerContext("203.0.113.7"), "203.0.113.7"},
+		{"ipv6", peerContext("2001:db8::1"), "2001:db8::1"},
+		{"unix", peer.NewContext(context.Background(), &peer.Peer{Addr: &net.UnixAddr{Name: "/tmp/grpc.sock"}}),
+			"/tmp/grpc.sock"},
+		{"no peer", context.Background(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := peerIP(tt.ctx); got != tt.want {
+				t.Errorf("peerIP %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
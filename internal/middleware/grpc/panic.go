@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicUnaryInterceptor turns a panic in the handler into an INTERNAL status.
+func PanicUnaryInterceptor(logger logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				ctxLogger(ctx, logger).Error("panic recovered", zap.Any("error", r), zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func PanicStreamInterceptor(logger logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				ctxLogger(ss.Context(), logger).Error("panic recovered", zap.Any("error", r),
+					zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
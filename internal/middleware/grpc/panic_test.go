@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPanicInterceptors(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(core)
+
+	_, err := PanicUnaryInterceptor(logger)(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/order.v1.OrderService/GetOrder"},
+		func(context.Context, any) (any, error) { panic("boom") })
+	if status.Code(err) != codes.Internal {
+		t.Errorf("unary err %v, want Internal", err)
+	}
+
+	err = PanicStreamInterceptor(logger)(nil, &fakeStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/order.v1.OrderService/ListOrders"},
+		func(any, grpc.ServerStream) error { panic("boom") })
+	if status.Code(err) != codes.Internal {
+		t.Errorf("stream err %v, want Internal", err)
+	}
+
+	if n := logs.FilterMessage("panic recovered").Len(); n != 2 {
+		t.Errorf("%d panics logged, want 2", n)
+	}
+}
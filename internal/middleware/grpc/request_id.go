@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func RequestIDUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestID(ctx, logger), req)
+	}
+}
+
+func RequestIDStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context(), logger)})
+	}
+}
+
+func withRequestID(ctx context.Context, logger *zap.Logger) context.Context {
+	key := strings.ToLower(definitions.HeaderRequestID)
+
+	var requestID string
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		requestID = values[0]
+	}
+	if !logctx.ValidRequestID(requestID) {
+		requestID = logctx.NewRequestID()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(key, requestID))
+	return logctx.With(ctx, logger, requestID)
+}
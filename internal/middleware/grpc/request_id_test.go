@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func TestRequestIDStreamInterceptor(t *testing.T) {
+	key := strings.ToLower(definitions.HeaderRequestID)
+	valid := logctx.NewRequestID()
+
+	tests := []struct {
+		name, incoming string
+		kept           bool
+	}{
+		{"propagated", valid, true},
+		{"invalid replaced", "not a request id\n", false},
+		{"missing generated", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.incoming != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(key, tt.incoming))
+			}
+			var requestID string
+			handler := func(_ any, ss grpc.ServerStream) error {
+				requestID = logctx.RequestID(ss.Context())
+				if _, ok := logctx.Logger(ss.Context()); !ok {
+					t.Error("no request logger in the context")
+				}
+				return nil
+			}
+			if err := RequestIDStreamInterceptor(zap.NewNop())(nil, &fakeStream{ctx: ctx}, nil, handler); err != nil {
+				t.Fatal(err)
+			}
+
+			if !logctx.ValidRequestID(requestID) || (requestID == tt.incoming) != tt.kept {
+				t.Errorf("request ID %q for incoming %q", requestID, tt.incoming)
+			}
+		})
+	}
+}
+
+func TestRequestIDUnaryInterceptor(t *testing.T) {
+	key := strings.ToLower(definitions.HeaderRequestID)
+	incoming := logctx.NewRequestID()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(key, incoming))
+
+	var requestID string
+	_, err := RequestIDUnaryInterceptor(zap.NewNop())(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, _ any) (any, error) {
+			requestID = logctx.RequestID(ctx)
+			return nil, nil
+		})
+	if err != nil || requestID != incoming {
+		t.Errorf("err %v, request ID %q, want %q", err, requestID, incoming)
+	}
+}
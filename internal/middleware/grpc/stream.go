@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+type (
+	logger interface {
+		Info(msg string, fields ...zap.Field)
+		Warn(msg string, fields ...zap.Field)
+		Error(msg string, fields ...zap.Field)
+	}
+
+	contextStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func ctxLogger(ctx context.Context, fallback logger) logger {
+	if l, ok := logctx.Logger(ctx); ok {
+		return l
+	}
+	return fallback
+}
@@ -1,27 +1,77 @@
 package http
 
 import (
+	"context"
+	"math/rand/v2"
 	"net/http"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+const unmatchedRoute = "unmatched"
+
 type (
 	logger interface {
 		Info(msg string, fields ...zap.Field)
+		Warn(msg string, fields ...zap.Field)
 		Error(msg string, fields ...zap.Field)
 	}
+
+	routeKey struct{}
 )
 
-func AccessLogMiddleware(next http.Handler, logger logger) http.Handler {
+// RouteMiddleware wraps the mux: middlewares between it and
+// AccessLogMiddleware clone the request, so r.Pattern does not reach the log.
+func RouteMiddleware(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+				*route = r.Pattern
+			}
+		}()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// AccessLogMiddleware samples only responses below 400.
+func AccessLogMiddleware(next http.Handler, successSampleRate float64, logger logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		next.ServeHTTP(w, r)
-		logger.Info("response",
+		rec := newResponseRecorder(w)
+		route := ""
+		r = r.WithContext(context.WithValue(r.Context(), routeKey{}, &route))
+		next.ServeHTTP(rec, r)
+
+		status := rec.Status()
+		if status < http.StatusBadRequest && rand.Float64() >= successSampleRate { //nolint:gosec
+			return
+		}
+
+		if route == "" {
+			route = r.Pattern
+		}
+		if route == "" {
+			route = unmatchedRoute
+		}
+		fields := []zap.Field{
 			zap.String("method", r.Method),
+			zap.String("route", route),
+			zap.Int("status", status),
+			zap.Int64("bytes", rec.Bytes()),
+			zap.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
 			zap.String("remote_address", r.RemoteAddr),
-			zap.String("path", r.URL.Path),
-			zap.String("response_time", time.Since(start).String()))
+			zap.String("user_agent", r.UserAgent()),
+		}
+
+		l := ctxLogger(r, logger)
+		switch {
+		case status >= http.StatusInternalServerError:
+			l.Error("response", fields...)
+		case status >= http.StatusBadRequest:
+			l.Warn("response", fields...)
+		default:
+			l.Info("response", fields...)
+		}
 	})
 }
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		sampleRate float64
+		level      zapcore.Level
+		logged     bool
+	}{
+		{"success", http.StatusOK, 1, zapcore.InfoLevel, true},
+		{"sampled out success", http.StatusOK, 0, zapcore.InfoLevel, false},
+		{"sampled out redirect", http.StatusFound, 0, zapcore.InfoLevel, false},
+		{"client error", http.StatusNotFound, 0, zapcore.WarnLevel, true},
+		{"server error", http.StatusBadGateway, 0, zapcore.ErrorLevel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /order/{order_uid}", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("hello"))
+			})
+			h := AccessLogMiddleware(mux, tt.sampleRate, zap.New(core))
+
+			r := httptest.NewRequest(http.MethodGet, "/order/b563feb7b2b84b6test", nil)
+			r.Header.Set("User-Agent", "test-agent")
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			entries := logs.All()
+			if !tt.logged {
+				if len(entries) != 0 {
+					t.Fatalf("logged %v", entries)
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("%d log entries", len(entries))
+			}
+			entry := entries[0]
+			fields := entry.ContextMap()
+			if entry.Level != tt.level || fields["status"] != int64(tt.status) || fields["bytes"] != int64(5) ||
+				fields["route"] != "GET /order/{order_uid}" || fields["user_agent"] != "test-agent" {
+				t.Errorf("entry %v %v", entry.Level, fields)
+			}
+		})
+	}
+}
+
+func TestAccessLogUnmatchedRoute(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	AccessLogMiddleware(http.NotFoundHandler(), 1, zap.New(core)).ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "/secret/path", nil))
+
+	if route := logs.All()[0].ContextMap()["route"]; route != unmatchedRoute {
+		t.Errorf("route %v", route)
+	}
+}
+
+func TestResponseRecorder(t *testing.T) {
+	rec := newResponseRecorder(httptest.NewRecorder())
+	if rec.Status() != http.StatusOK {
+		t.Errorf("status %d with nothing written", rec.Status())
+	}
+
+	rec.WriteHeader(http.StatusCreated)
+	rec.WriteHeader(http.StatusInternalServerError)
+	_, _ = rec.Write([]byte("abc"))
+	_, _ = rec.Write([]byte("de"))
+	if rec.Status() != http.StatusCreated || rec.Bytes() != 5 {
+		t.Errorf("status %d, bytes %d", rec.Status(), rec.Bytes())
+	}
+
+	flushed := newResponseRecorder(httptest.NewRecorder())
+	flushed.Flush()
+	if flushed.Status() != http.StatusOK {
+		t.Errorf("status %d after Flush", flushed.Status())
+	}
+
+	if _, _, err := rec.Hijack(); err == nil {
+		t.Error("Hijack succeeded on a writer without http.Hijacker")
+	}
+	if err := http.NewResponseController(rec).Flush(); err != nil {
+		t.Errorf("ResponseController.Flush: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+	authRealm    = "wbtech-l0"
+)
+
+type (
+	apiKey struct {
+		name   string
+		scopes []string
+	}
+	jwtClaims struct {
+		jwt.RegisteredClaims
+		Scope string   `json:"scope"`
+		Scp   []string `json:"scp"`
+	}
+
+	Authenticator struct {
+		disabled bool
+		apiKeys  map[string]apiKey // by hex SHA-256 of the key
+		jwtKeys  map[string]verificationKey
+		parser   *jwt.Parser
+	}
+)
+
+var (
+	ErrUnauthenticated   = appHttp.NewProblemError(http.StatusUnauthorized, "unauthenticated", "authentication required")
+	ErrInvalidCredential = appHttp.NewProblemError(http.StatusUnauthorized, "invalid_credentials", "invalid credentials")
+	ErrForbidden         = appHttp.NewProblemError(http.StatusForbidden, "insufficient_scope", "insufficient scope")
+)
+
+func NewAuthenticator(cfg AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys: make(map[string]apiKey, len(cfg.APIKeys)),
+		jwtKeys: map[string]verificationKey{},
+	}
+
+	for _, k := range cfg.APIKeys {
+		hash := strings.ToLower(k.SHA256)
+		if len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("api key %q: sha256 must be %d hex chars", k.Name, sha256.Size*2)
+		}
+		a.apiKeys[hash] = apiKey{
+			name:   k.Name,
+			scopes: k.Scopes,
+		}
+	}
+
+	if cfg.JWT.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWT.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwtKeys = keys
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWT.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWT.Issuer))
+	}
+	if cfg.JWT.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWT.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+func NewDisabledAuthenticator() *Authenticator {
+	return &Authenticator{
+		disabled: true,
+	}
+}
+
+func (a *Authenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	return a.AuthenticateCredentials(r.Header.Get(apiKeyHeader), r.Header.Get("Authorization"))
+}
+
+func (a *Authenticator) AuthenticateCredentials(key, authorization string) (auth.Principal, error) {
+	if a.disabled {
+		return auth.Anonymous, nil
+	}
+
+	if key != "" {
+		sum := sha256.Sum256([]byte(key))
+		k, inMap := a.apiKeys[hex.EncodeToString(sum[:])]
+		if !inMap {
+			return auth.Principal{}, fmt.Errorf("%w: unknown api key", ErrInvalidCredential)
+		}
+		return auth.Principal{
+			Subject: k.name,
+			Method:  "api_key",
+			Scopes:  k.scopes,
+		}, nil
+	}
+
+	if token, found := strings.CutPrefix(authorization, bearerPrefix); found {
+		return a.authenticateJWT(token)
+	}
+
+	return auth.Principal{}, ErrUnauthenticated
+}
+
+func (a *Authenticator) authenticateJWT(token string) (auth.Principal, error) {
+	claims := &jwtClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, a.jwtKey)
+	if err != nil {
+		return auth.Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	scopes := claims.Scp
+	if claims.Scope != "" {
+		scopes = append(scopes, strings.Fields(claims.Scope)...)
+	}
+
+	return auth.Principal{
+		Subject: claims.Subject,
+		Method:  "jwt",
+		Scopes:  scopes,
+	}, nil
+}
+
+// jwtKey refuses tokens whose alg does not match the key type.
+func (a *Authenticator) jwtKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, inMap := a.jwtKeys[kid]
+	if !inMap {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("alg %q does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.key, nil
+}
+
+func AuthMiddleware(next http.Handler, authenticator *Authenticator, scope string, logger logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, authenticated := auth.PrincipalFromContext(r.Context())
+		var err error
+		if !authenticated {
+			principal, err = authenticator.Authenticate(r)
+		}
+		if err != nil {
+			ctxLogger(r, logger).Info("authentication failed", zap.String("path", r.URL.Path), zap.Error(err))
+			respErr := ErrInvalidCredential
+			if errors.Is(err, ErrUnauthenticated) {
+				respErr = ErrUnauthenticated
+			}
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", authRealm))
+			appHttp.WriteProblem(w, r, respErr, "")
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			ctxLogger(r, logger).Info("access denied", zap.String("path", r.URL.Path),
+				zap.String("subject", principal.Subject), zap.String("scope", scope))
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf("Bearer realm=%q, error=\"insufficient_scope\", scope=%q", authRealm, scope))
+			appHttp.WriteProblem(w, r, ErrForbidden, fmt.Sprintf("scope %q required", scope))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
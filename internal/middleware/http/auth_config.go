@@ -0,0 +1,112 @@
+package http
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type (
+	AuthConfig struct {
+		APIKeys []APIKeyConfig `json:"api_keys"`
+		JWT     JWTConfig      `json:"jwt"`
+	}
+	APIKeyConfig struct {
+		Name   string   `json:"name"`
+		SHA256 string   `json:"sha256"`
+		Scopes []string `json:"scopes"`
+	}
+	JWTConfig struct {
+		JWKSFile string `json:"jwks_file"`
+		Issuer   string `json:"issuer"`
+		Audience string `json:"audience"`
+	}
+
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		K   string `json:"k"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	verificationKey struct {
+		alg string
+		key any
+	}
+)
+
+var ErrInvalidJWK = errors.New("invalid jwk")
+
+func LoadAuthConfig(path string) (AuthConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AuthConfig{}, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	cfg := AuthConfig{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return AuthConfig{}, fmt.Errorf("auth config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func loadJWKS(path string) (map[string]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	set := jwks{}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", path, err)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks %s: kid %q: %w", path, k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) verificationKey() (verificationKey, error) {
+	switch {
+	case k.Kty == "oct" && (k.Alg == "HS256" || k.Alg == ""):
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return verificationKey{}, fmt.Errorf("%w: bad k", ErrInvalidJWK)
+		}
+		return verificationKey{alg: "HS256", key: secret}, nil
+
+	case k.Kty == "RSA" && (k.Alg == "RS256" || k.Alg == ""):
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 {
+			return verificationKey{}, fmt.Errorf("%w: bad n or e", ErrInvalidJWK)
+		}
+		return verificationKey{
+			alg: "RS256",
+			key: &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			},
+		}, nil
+
+	default:
+		return verificationKey{}, fmt.Errorf("%w: unsupported kty %q alg %q", ErrInvalidJWK, k.Kty, k.Alg)
+	}
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+)
+
+const (
+	testAPIKey    = "s3cret-key"
+	testIssuer    = "https://issuer.example"
+	testAudience  = "orders-api"
+	testHMACKid   = "hmac"
+	testRSAKid    = "rsa"
+	testHMACValue = "0123456789abcdef0123456789abcdef"
+)
+
+type testKeys struct {
+	authenticator *Authenticator
+	rsaKey        *rsa.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := base64.RawURLEncoding.EncodeToString
+	set := jwks{Keys: []jwk{
+		{Kty: "oct", Kid: testHMACKid, Alg: "HS256", K: encode([]byte(testHMACValue))},
+		{Kty: "RSA", Kid: testRSAKid, Alg: "RS256", N: encode(rsaKey.N.Bytes()),
+			E: encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte(testAPIKey))
+	authenticator, err := NewAuthenticator(AuthConfig{
+		APIKeys: []APIKeyConfig{{Name: "support", SHA256: strings.ToUpper(hex.EncodeToString(sum[:])),
+			Scopes: []string{auth.ScopeOrdersRead}}},
+		JWT: JWTConfig{JWKSFile: path, Issuer: testIssuer, Audience: testAudience},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	return testKeys{authenticator: authenticator, rsaKey: rsaKey}
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "orders:read reports:read",
+		"scp":   []string{"orders:write"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	a := newTestAuthenticator(t).authenticator
+
+	p, err := a.AuthenticateCredentials(testAPIKey, "")
+	if err != nil {
+		t.Fatalf("AuthenticateCredentials: %v", err)
+	}
+	if p.ID() != "api_key:support" || !p.HasScope(auth.ScopeOrdersRead) {
+		t.Errorf("principal %+v", p)
+	}
+
+	if _, err := a.AuthenticateCredentials("wrong", ""); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("unknown key: %v", err)
+	}
+	if _, err := a.AuthenticateCredentials("", ""); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("no credentials: %v", err)
+	}
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	keys := newTestAuthenticator(t)
+
+	for name, token := range map[string]string{
+		"HS256": sign(t, jwt.SigningMethodHS256, testHMACKid, testClaims(), []byte(testHMACValue)),
+		"RS256": sign(t, jwt.SigningMethodRS256, testRSAKid, testClaims(), keys.rsaKey),
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, err := keys.authenticator.AuthenticateCredentials("", bearerPrefix+token)
+			if err != nil {
+				t.Fatalf("AuthenticateCredentials: %v", err)
+			}
+			if p.ID() != "jwt:alice" {
+				t.Errorf("principal %+v", p)
+			}
+			for _, scope := range []string{auth.ScopeOrdersRead, auth.ScopeReportsRead, auth.ScopeOrdersWrite} {
+				if !p.HasScope(scope) {
+					t.Errorf("scope %q not granted: %v", scope, p.Scopes)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticateJWTRejected(t *testing.T) {
+	keys := newTestAuthenticator(t)
+	with := func(key string, value any) jwt.MapClaims {
+		claims := testClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	hmac := []byte(testHMACValue)
+
+	tests := map[string]string{
+		"expired":      sign(t, jwt.SigningMethodHS256, testHMACKid, with("exp", time.Now().Add(-time.Minute).Unix()), hmac),
+		"no exp":       sign(t, jwt.SigningMethodHS256, testHMACKid, with("exp", nil), hmac),
+		"issuer":       sign(t, jwt.SigningMethodHS256, testHMACKid, with("iss", "https://other.example"), hmac),
+		"audience":     sign(t, jwt.SigningMethodHS256, testHMACKid, with("aud", "other"), hmac),
+		"unknown kid":  sign(t, jwt.SigningMethodHS256, "other", testClaims(), hmac),
+		"bad secret":   sign(t, jwt.SigningMethodHS256, testHMACKid, testClaims(), []byte("wrong secret")),
+		"alg mismatch": sign(t, jwt.SigningMethodHS256, testRSAKid, testClaims(), keys.rsaKey.N.Bytes()),
+		"HS512":        sign(t, jwt.SigningMethodHS512, testHMACKid, testClaims(), hmac),
+		"malformed":    "not.a.jwt",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := keys.authenticator.AuthenticateCredentials("", bearerPrefix+token)
+			if !errors.Is(err, ErrInvalidCredential) {
+				t.Fatalf("error %v, want ErrInvalidCredential", err)
+			}
+		})
+	}
+}
+
+func TestNewAuthenticatorRejectsBadKeyHash(t *testing.T) {
+	_, err := NewAuthenticator(AuthConfig{APIKeys: []APIKeyConfig{{Name: "short", SHA256: "abcd"}}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestDisabledAuthenticator(t *testing.T) {
+	p, err := NewDisabledAuthenticator().AuthenticateCredentials("", "")
+	if err != nil || p.ID() != auth.Anonymous.ID() {
+		t.Fatalf("principal %+v, %v", p, err)
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	a := newTestAuthenticator(t).authenticator
+
+	tests := []struct {
+		name, key, scope, wantAuthenticate string
+		status                             int
+	}{
+		{"granted", testAPIKey, auth.ScopeOrdersRead, "", http.StatusOK},
+		{"no credentials", "", auth.ScopeOrdersRead, `Bearer realm="wbtech-l0"`, http.StatusUnauthorized},
+		{"unknown key", "wrong", auth.ScopeOrdersRead, `Bearer realm="wbtech-l0"`, http.StatusUnauthorized},
+		{"insufficient scope", testAPIKey, auth.ScopeOrdersReadPII,
+			`Bearer realm="wbtech-l0", error="insufficient_scope", scope="orders:read:pii"`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen auth.Principal
+			h := AuthMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				seen, _ = auth.PrincipalFromContext(r.Context())
+			}), a, tt.scope, zap.NewNop())
+			req := httptest.NewRequest(http.MethodGet, "/orders/x", nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantAuthenticate {
+				t.Errorf("WWW-Authenticate %q, want %q", got, tt.wantAuthenticate)
+			}
+			if tt.status == http.StatusOK && seen.ID() != "api_key:support" {
+				t.Errorf("principal in context %+v", seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	trusted := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, p := range trustedProxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			addr, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+			}
+			trusted = append(trusted, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+
+	return &ClientIPResolver{
+		trusted: trusted,
+	}, nil
+}
+
+// ClientIP returns the rightmost X-Forwarded-For hop that is not a trusted proxy.
+func (c *ClientIPResolver) ClientIP(r *http.Request) string {
+	remote := remoteIP(r.RemoteAddr)
+	if !c.isTrusted(remote) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !c.isTrusted(hop) {
+			return hop
+		}
+	}
+
+	return remote
+}
+
+func (c *ClientIPResolver) isTrusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range c.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
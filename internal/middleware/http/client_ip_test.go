@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	ips, err := NewClientIPResolver([]string{"10.0.0.0/8", " 192.168.1.1 ", ""})
+	if err != nil {
+		t.Fatalf("NewClientIPResolver: %v", err)
+	}
+
+	tests := []struct {
+		name, remote string
+		forwarded    []string
+		want         string
+	}{
+		{"direct", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted sender spoofs the header", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost hop", "10.1.2.3:5000", []string{"1.1.1.1, 198.51.100.1, 192.168.1.1"}, "198.51.100.1"},
+		{"several headers", "10.1.2.3:5000", []string{"1.1.1.1", "198.51.100.1"}, "198.51.100.1"},
+		{"only proxies", "10.1.2.3:5000", []string{"10.9.9.9"}, "10.1.2.3"},
+		{"ipv4-mapped proxy", "[::ffff:10.1.2.3]:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"ipv6 client", "[2001:db8::1]:5000", nil, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := ips.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverRejectsInvalid(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.local"} {
+		if _, err := NewClientIPResolver([]string{proxy}); err == nil {
+			t.Errorf("%q accepted", proxy)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingZstd   = "zstd"
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	brotliLevel = 5
+	sniffLen    = 512
+)
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressionEncodings are in server preference order, which breaks q-value ties.
+var compressionEncodings = [...]string{encodingZstd, encodingBrotli, encodingGzip}
+
+var encoderPools = map[string]*sync.Pool{
+	encodingZstd: {New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithLowerEncoderMem(true))
+		return enc
+	}},
+	encodingBrotli: {New: func() any {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}},
+	encodingGzip: {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// CompressMiddleware skips bodies shorter than minSize, encoded and partial
+// responses and types that do not compress well.
+func CompressMiddleware(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		next.ServeHTTP(cw, r)
+		cw.finish()
+	})
+}
+
+// negotiateEncoding returns "" for identity.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	var (
+		qualities [len(compressionEncodings)]float64
+		listed    [len(compressionEncodings)]bool
+		starQ     float64
+		star      bool
+	)
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if name == "*" {
+			starQ, star = q, true
+			continue
+		}
+		for i, encoding := range compressionEncodings {
+			if strings.EqualFold(name, encoding) {
+				qualities[i], listed[i] = q, true
+			}
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for i, encoding := range compressionEncodings {
+		q, ok := qualities[i], listed[i]
+		if !ok {
+			q, ok = starQ, star
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/javascript", "application/xml":
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers up to minSize bytes; Flush decides at once.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status   int
+	buf      []byte
+	decided  bool
+	hijacked bool
+	enc      encoder
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.decided || status < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.decided {
+		return cw.write(p)
+	}
+
+	if len(cw.buf)+len(p) < cw.minSize {
+		cw.buf = append(cw.buf, p...)
+		return len(p), nil
+	}
+	if err := cw.decide(true, p); err != nil {
+		return 0, err
+	}
+	return cw.write(p)
+}
+
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(true, nil); err != nil {
+			return
+		}
+	}
+	if cw.enc != nil {
+		if err := cw.enc.Flush(); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement http.Hijacker", cw.ResponseWriter)
+	}
+	cw.hijacked = true
+	return h.Hijack()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) write(p []byte) (int, error) {
+	if cw.enc != nil {
+		return cw.enc.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide sends the headers and the buffered body; next is only sniffed.
+func (cw *compressWriter) decide(allowCompression bool, next []byte) error {
+	cw.decided = true
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	header := cw.Header()
+	if header.Get("Content-Type") == "" && len(cw.buf)+len(next) > 0 {
+		header.Set("Content-Type", http.DetectContentType(cw.sniffed(next)))
+	}
+
+	if allowCompression && cw.shouldCompress() {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", cw.encoding)
+		weakenETag(header)
+		cw.enc = encoderPools[cw.encoding].Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+
+	if cw.status == http.StatusNotModified {
+		weakenETag(header)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.write(buf)
+	return err
+}
+
+func (cw *compressWriter) sniffed(next []byte) []byte {
+	if len(cw.buf) == 0 {
+		return next
+	}
+	if len(cw.buf) >= sniffLen || len(next) == 0 {
+		return cw.buf
+	}
+	return append(cw.buf[:len(cw.buf):len(cw.buf)], next[:min(len(next), sniffLen-len(cw.buf))]...)
+}
+
+func weakenETag(header http.Header) {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+}
+
+func (cw *compressWriter) shouldCompress() bool {
+	switch cw.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+	header := cw.Header()
+	return header.Get("Content-Encoding") == "" && compressibleType(header.Get("Content-Type"))
+}
+
+func (cw *compressWriter) finish() {
+	if cw.hijacked {
+		return
+	}
+	if !cw.decided {
+		if err := cw.decide(false, nil); err != nil {
+			return
+		}
+	}
+	if cw.enc != nil {
+		if err := cw.enc.Close(); err != nil {
+			return
+		}
+		encoderPools[cw.encoding].Put(cw.enc)
+		cw.enc = nil
+	}
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/testutil/fixtures"
+)
+
+const testMinSize = 1024
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding, want string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"gzip, deflate, br", encodingBrotli},
+		{"gzip, br, zstd", encodingZstd},
+		{"GZIP;q=0.5, br;q=0.8", encodingBrotli},
+		{"gzip;q=1.0, br;q=0.8", encodingGzip},
+		{"br;q=0, gzip", encodingGzip},
+		{"*", encodingZstd},
+		{"*;q=0.5, zstd;q=0", encodingBrotli},
+		{"gzip;q=abc", ""},
+		{"deflate, compress", ""},
+		{" gzip ; q=0.3 ,br ; q=0.2", encodingGzip},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.acceptEncoding); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+		}
+	}
+}
+
+func decompress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case encodingGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	case encodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	case encodingZstd:
+		dec, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer dec.Close()
+		r = dec
+	default:
+		return body
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%s: %v", encoding, err)
+	}
+	return data
+}
+
+func ordersJSON(n int) []byte {
+	data, _ := json.Marshal(fixtures.New(1).Orders(n))
+	return data
+}
+
+func serveCompressed(h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if acceptEncoding != "" {
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	body := ordersJSON(50)
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(body[:100])
+		_, _ = w.Write(body[100:])
+	}), testMinSize)
+
+	for _, encoding := range []string{encodingZstd, encodingBrotli, encodingGzip, ""} {
+		t.Run("encoding="+encoding, func(t *testing.T) {
+			for range 2 {
+				rec := serveCompressed(h, encoding)
+
+				if got := rec.Header().Get("Content-Encoding"); got != encoding {
+					t.Fatalf("Content-Encoding %q, want %q", got, encoding)
+				}
+				if got := decompress(t, encoding, rec.Body.Bytes()); !bytes.Equal(got, body) {
+					t.Fatalf("body differs after %q round trip", encoding)
+				}
+				if encoding != "" && rec.Body.Len() >= len(body) {
+					t.Errorf("%d compressed bytes of %d", rec.Body.Len(), len(body))
+				}
+				wantETag := `"v1"`
+				if encoding != "" {
+					wantETag = `W/"v1"`
+				}
+				if got := rec.Header().Get("ETag"); got != wantETag {
+					t.Errorf("ETag %q, want %q", got, wantETag)
+				}
+				if !strings.Contains(rec.Header().Get("Vary"), "Accept-Encoding") {
+					t.Errorf("Vary %q", rec.Header().Get("Vary"))
+				}
+			}
+		})
+	}
+}
+
+func TestCompressMiddlewareSkips(t *testing.T) {
+	large := ordersJSON(50)
+	tests := []struct {
+		name, contentType, encoding string
+		status                      int
+		body                        []byte
+	}{
+		{"small body", "application/json", "", http.StatusOK, large[:testMinSize-1]},
+		{"incompressible type", "image/png", "", http.StatusOK, large},
+		{"already encoded", "application/json", "identity", http.StatusOK, large},
+		{"partial content", "text/plain", "", http.StatusPartialContent, large},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write(tt.body)
+			}), testMinSize)
+			rec := serveCompressed(h, "gzip")
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Fatalf("Content-Encoding %q, want %q", got, tt.encoding)
+			}
+			if rec.Code != tt.status || !bytes.Equal(rec.Body.Bytes(), tt.body) {
+				t.Errorf("status %d, body changed", rec.Code)
+			}
+		})
+	}
+}
+
+func TestCompressMiddlewareNotModified(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusNotModified)
+	}), testMinSize)
+
+	rec := serveCompressed(h, "br")
+	if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != `W/"v1"` ||
+		rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("status %d, headers %v", rec.Code, rec.Header())
+	}
+}
+
+func TestCompressMiddlewareStreams(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+		_, _ = w.Write([]byte("data: 2\n\n"))
+	}), testMinSize)
+
+	rec := serveCompressed(h, "gzip")
+	if rec.Header().Get("Content-Encoding") != encodingGzip || !rec.Flushed {
+		t.Fatalf("headers %v, flushed %v", rec.Header(), rec.Flushed)
+	}
+	if got := decompress(t, encodingGzip, rec.Body.Bytes()); string(got) != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("body %q", got)
+	}
+}
+
+func BenchmarkNegotiateEncoding(b *testing.B) {
+	for _, acceptEncoding := range []string{"gzip, deflate, br, zstd", "gzip;q=1.0, br;q=0.8, *;q=0.1", ""} {
+		b.Run(acceptEncoding, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				negotiateEncoding(acceptEncoding)
+			}
+		})
+	}
+}
+
+func BenchmarkCompressMiddleware(b *testing.B) {
+	body := ordersJSON(20)
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}), testMinSize)
+
+	for _, encoding := range []string{encodingZstd, encodingBrotli, encodingGzip, "identity"} {
+		b.Run(encoding, func(b *testing.B) {
+			r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			r.Header.Set("Accept-Encoding", encoding)
+			w := &discardWriter{header: http.Header{}}
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for b.Loop() {
+				clear(w.header)
+				w.written = 0
+				h.ServeHTTP(w, r)
+			}
+			b.ReportMetric(float64(w.written)/float64(len(body)), "ratio")
+		})
+	}
+}
+
+type discardWriter struct {
+	header  http.Header
+	written int
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) Write(p []byte) (int, error) {
+	w.written += len(p)
+	return len(p), nil
+}
+
+func (w *discardWriter) WriteHeader(int) {}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"expvar"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
+)
+
+const (
+	overloadRetryAfter   = "1"
+	rejectionLogInterval = 10 * time.Second
+)
+
+var (
+	ErrRateLimited = appHttp.NewProblemError(http.StatusTooManyRequests, "rate_limited", "too many requests")
+	ErrOverloaded  = appHttp.NewProblemError(http.StatusServiceUnavailable, "overloaded", "server is overloaded")
+
+	rejections = expvar.NewMap("http_rejections")
+)
+
+// RateLimitMiddleware keys the limiter by the verified principal, which it
+// stores for AuthMiddleware, or by client IP.
+func RateLimitMiddleware(next http.Handler, limiter *ratelimit.Limiter, authenticator *Authenticator,
+	ips *ClientIPResolver, logger logger,
+) http.Handler {
+	logSometimes := &rate.Sometimes{Interval: rejectionLogInterval}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticator.Authenticate(r)
+		if err == nil {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+		}
+
+		key := ratelimit.Key(principal, err == nil, ips.ClientIP(r))
+		if delay := limiter.Reserve(key); delay > 0 {
+			rejections.Add("rate_limited", 1)
+			logSometimes.Do(func() {
+				ctxLogger(r, logger).Warn("rate limit exceeded", zap.String("client", key),
+					zap.String("path", r.URL.Path))
+			})
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			appHttp.WriteProblem(w, r, ErrRateLimited, "")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func InFlightLimitMiddleware(next http.Handler, maxInFlight int, logger logger) http.Handler {
+	slots := make(chan struct{}, maxInFlight)
+	logSometimes := &rate.Sometimes{Interval: rejectionLogInterval}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+			next.ServeHTTP(w, r)
+		default:
+			rejections.Add("overloaded", 1)
+			logSometimes.Do(func() {
+				ctxLogger(r, logger).Warn("in-flight limit reached", zap.Int("max_in_flight", maxInFlight),
+					zap.String("path", r.URL.Path))
+			})
+
+			w.Header().Set("Retry-After", overloadRetryAfter)
+			appHttp.WriteProblem(w, r, ErrOverloaded, "")
+		}
+	})
+}
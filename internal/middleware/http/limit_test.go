@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/auth"
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ratelimit"
+)
+
+func limitedRequest(h http.Handler, remote, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/orders/x", nil)
+	r.RemoteAddr = remote
+	if key != "" {
+		r.Header.Set(apiKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ips, err := NewClientIPResolver(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen auth.Principal
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen, _ = auth.PrincipalFromContext(r.Context())
+	})
+	h := RateLimitMiddleware(next, ratelimit.New(1, 1, 100), newTestAuthenticator(t).authenticator, ips, zap.NewNop())
+
+	if rec := limitedRequest(h, "203.0.113.7:1", testAPIKey); rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if seen.ID() != "api_key:support" {
+		t.Errorf("principal in context %+v", seen)
+	}
+
+	rec := limitedRequest(h, "203.0.113.8:1", testAPIKey)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want the key limited across IPs", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After %q", got)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"code":"rate_limited"`) {
+		t.Errorf("body %s", body)
+	}
+
+	if rec := limitedRequest(h, "203.0.113.7:1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want the IP limited apart from the key", rec.Code)
+	}
+	for i := range 3 {
+		if rec := limitedRequest(h, "203.0.113.7:1", "forged-"+strconv.Itoa(i)); rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("status %d, want unverified keys limited by IP", rec.Code)
+		}
+	}
+}
+
+func TestAuthMiddlewareUsesVerifiedPrincipal(t *testing.T) {
+	h := AuthMiddleware(http.NotFoundHandler(), newTestAuthenticator(t).authenticator, auth.ScopeOrdersRead,
+		zap.NewNop())
+
+	r := httptest.NewRequest(http.MethodGet, "/orders/x", nil)
+	r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{Scopes: []string{auth.ScopeOrdersRead}}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want the stored principal let through", rec.Code)
+	}
+}
+
+func TestInFlightLimitMiddleware(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	h := InFlightLimitMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		entered <- struct{}{}
+		<-release
+	}), 1, zap.NewNop())
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		limitedRequest(h, "203.0.113.7:1", "")
+	}()
+	<-entered
+
+	rec := limitedRequest(h, "203.0.113.7:1", "")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != overloadRetryAfter {
+		t.Fatalf("status %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	wg.Wait()
+}
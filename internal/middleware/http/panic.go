@@ -4,14 +4,19 @@ import (
 	"net/http"
 
 	"go.uber.org/zap"
+
+	appHttp "github.com/AndrejDubinin/wbtech-l0/internal/app/http"
 )
 
 func PanicMiddleware(next http.Handler, logger logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Error("panic recovered", zap.Any("error", err))
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				if err == http.ErrAbortHandler { //nolint:errorlint
+					panic(err)
+				}
+				ctxLogger(r, logger).Error("panic recovered", zap.Any("error", err))
+				appHttp.WriteProblem(w, r, appHttp.ErrInternalServerError, "")
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func RequestIDMiddleware(next http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(definitions.HeaderRequestID)
+		if !logctx.ValidRequestID(requestID) {
+			requestID = logctx.NewRequestID()
+		}
+
+		w.Header().Set(definitions.HeaderRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(logctx.With(r.Context(), logger, requestID)))
+	})
+}
+
+func ctxLogger(r *http.Request, fallback logger) logger {
+	if l, ok := logctx.Logger(r.Context()); ok {
+		return l
+	}
+	return fallback
+}
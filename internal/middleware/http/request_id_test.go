@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/definitions"
+	"github.com/AndrejDubinin/wbtech-l0/internal/infra/logctx"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	var seen string
+	h := RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = logctx.RequestID(r.Context())
+		ctxLogger(r, zap.NewNop()).Info("handled")
+	}), zap.New(core))
+
+	tests := []struct {
+		name, header string
+		keep         bool
+	}{
+		{"client ID", "client-42", true},
+		{"no ID", "", false},
+		{"invalid ID", "bad id\r\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(definitions.HeaderRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			got := rec.Header().Get(definitions.HeaderRequestID)
+			if tt.keep && got != tt.header || !tt.keep && (got == tt.header || !logctx.ValidRequestID(got)) {
+				t.Errorf("response request ID %q", got)
+			}
+			if seen != got {
+				t.Errorf("context request ID %q, response %q", seen, got)
+			}
+			entries := logs.TakeAll()
+			if len(entries) != 1 || entries[0].ContextMap()[logctx.FieldRequestID] != got {
+				t.Errorf("log entries %v", entries)
+			}
+		})
+	}
+}
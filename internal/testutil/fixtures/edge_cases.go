@@ -0,0 +1,60 @@
+package fixtures
+
+import "github.com/AndrejDubinin/wbtech-l0/internal/domain"
+
+// MaxItems keeps the items INSERT below the 65535 parameters limit.
+const MaxItems = 1000
+
+const edgeCasesSeed = 0xed9e
+
+func EdgeCases() []domain.Order {
+	g := New(edgeCasesSeed)
+
+	orders := []domain.Order{
+		g.MaxItemsOrder(),
+		g.UnicodeOrder(),
+		g.ZeroSaleOrder(),
+	}
+	return append(orders, g.MultiCurrencyOrders()...)
+}
+
+func (g *Generator) MaxItemsOrder() domain.Order {
+	return g.order(MaxItems, pick(g.rnd, currencies))
+}
+
+func (g *Generator) UnicodeOrder() domain.Order {
+	order := g.order(3, "RUB")
+	order.Locale = "ru"
+	order.Delivery.Name = "Анна-Мария Щербакова-Ёлкина"
+	order.Delivery.City = "Санкт-Петербург"
+	order.Delivery.Region = "Ленинградская область"
+	order.Delivery.Address = "Невский проспект, д. 28, кв. «7»"
+
+	names := []string{"Тушь для ресниц", "运动鞋 👟", "Crème brûlée façon grand-mère"}
+	brands := []string{"Ёлка", "小米", "Ñandú"}
+	for i := range order.Items {
+		order.Items[i].Name = names[i]
+		order.Items[i].Brand = brands[i]
+	}
+
+	return order
+}
+
+func (g *Generator) ZeroSaleOrder() domain.Order {
+	order := g.order(3, "USD")
+	for i := range order.Items {
+		order.Items[i].Sale = 0
+		order.Items[i].TotalPrice = order.Items[i].Price
+	}
+	recalcPayment(&order)
+
+	return order
+}
+
+func (g *Generator) MultiCurrencyOrders() []domain.Order {
+	orders := make([]domain.Order, 0, len(currencies))
+	for _, currency := range currencies {
+		orders = append(orders, g.order(2, currency))
+	}
+	return orders
+}
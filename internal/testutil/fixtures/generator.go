@@ -0,0 +1,179 @@
+package fixtures
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const (
+	DefaultItemsMin = 1
+	DefaultItemsMax = 5
+
+	maxSale = 90
+)
+
+var (
+	defaultStartDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	firstNames = []string{"Ivan", "Maria", "Alex", "Olga", "John", "Anna", "Dmitry", "Elena"}
+	lastNames  = []string{"Petrov", "Ivanova", "Smith", "Sidorova", "Brown", "Kuznetsov"}
+	cities     = []struct{ city, region, zip string }{
+		{"Moscow", "Moscow", "101000"},
+		{"Saint Petersburg", "Leningrad Oblast", "190000"},
+		{"Kazan", "Tatarstan", "420000"},
+		{"Novosibirsk", "Novosibirsk Oblast", "630000"},
+		{"Kiryat Mozkin", "Kraiot", "2639809"},
+	}
+	streets    = []string{"Lenina", "Mira", "Sadovaya", "Tverskaya", "Ploshad Mira"}
+	brands     = []string{"Vivienne Sabo", "Nike", "Adidas", "Samsung", "Xiaomi", "Lego"}
+	products   = []string{"Mascaras", "Sneakers", "T-shirt", "Phone case", "Headphones", "Backpack"}
+	sizes      = []string{"0", "S", "M", "L", "XL", "42"}
+	providers  = []string{"wbpay", "cardpay", "sbp"}
+	banks      = []string{"alpha", "sber", "tinkoff", "vtb"}
+	services   = []string{"meest", "cdek", "boxberry", "wb"}
+	locales    = []string{"en", "ru", "en-US", "ru-RU"}
+	currencies = []string{"RUB", "USD", "EUR", "KZT", "BYN", "CNY"}
+)
+
+type Generator struct {
+	rnd      *rand.Rand
+	seq      int
+	now      time.Time
+	itemsMin int
+	itemsMax int
+}
+
+func New(seed uint64, opts ...Option) *Generator {
+	g := &Generator{
+		rnd:      rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)), //nolint:gosec
+		now:      defaultStartDate,
+		itemsMin: DefaultItemsMin,
+		itemsMax: DefaultItemsMax,
+	}
+	for _, opt := range opts {
+		opt.Apply(g)
+	}
+
+	return g
+}
+
+func (g *Generator) Orders(n int) []domain.Order {
+	orders := make([]domain.Order, 0, n)
+	for range n {
+		orders = append(orders, g.Order())
+	}
+	return orders
+}
+
+func (g *Generator) Order() domain.Order {
+	return g.order(g.itemsMin+g.rnd.IntN(g.itemsMax-g.itemsMin+1), pick(g.rnd, currencies))
+}
+
+func (g *Generator) order(itemsCount int, currency string) domain.Order {
+	g.seq++
+	g.now = g.now.Add(time.Duration(1+g.rnd.IntN(3600)) * time.Second)
+
+	orderUID := fmt.Sprintf("fx%06d%010x", g.seq, g.rnd.Uint64()&0xffffffffff)
+	trackNumber := fmt.Sprintf("WBIL%08d", g.rnd.IntN(100_000_000))
+
+	items := make([]domain.Item, 0, itemsCount)
+	for range itemsCount {
+		items = append(items, g.item(trackNumber))
+	}
+
+	order := domain.Order{
+		OrderUID:          orderUID,
+		TrackNumber:       trackNumber,
+		Entry:             "WBIL",
+		Delivery:          g.delivery(),
+		Items:             items,
+		Locale:            pick(g.rnd, locales),
+		InternalSignature: "",
+		CustomerID:        fmt.Sprintf("customer-%04d", g.rnd.IntN(1000)),
+		DeliveryService:   pick(g.rnd, services),
+		ShardKey:          fmt.Sprintf("%d", 1+g.rnd.IntN(9)),
+		SmID:              1 + g.rnd.IntN(100),
+		DateCreated:       g.now,
+		OofShard:          fmt.Sprintf("%d", 1+g.rnd.IntN(2)),
+	}
+	order.Payment = g.payment(orderUID, currency, items)
+
+	return order
+}
+
+func (g *Generator) delivery() domain.Delivery {
+	first, last := pick(g.rnd, firstNames), pick(g.rnd, lastNames)
+	place := cities[g.rnd.IntN(len(cities))]
+
+	return domain.Delivery{
+		Name:    first + " " + last,
+		Phone:   fmt.Sprintf("+79%09d", g.rnd.IntN(1_000_000_000)),
+		Zip:     place.zip,
+		City:    place.city,
+		Address: fmt.Sprintf("%s %d", pick(g.rnd, streets), 1+g.rnd.IntN(200)),
+		Region:  place.region,
+		Email:   fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), g.rnd.IntN(100)),
+	}
+}
+
+func (g *Generator) item(trackNumber string) domain.Item {
+	price := 100 + g.rnd.IntN(10_000)
+	sale := g.rnd.IntN(maxSale + 1)
+
+	return domain.Item{
+		ChrtID:      1_000_000 + g.rnd.IntN(9_000_000),
+		TrackNumber: trackNumber,
+		Price:       price,
+		RID:         fmt.Sprintf("%016x", g.rnd.Uint64()),
+		Name:        pick(g.rnd, products),
+		Sale:        sale,
+		Size:        pick(g.rnd, sizes),
+		TotalPrice:  totalPrice(price, sale),
+		NmID:        1_000_000 + g.rnd.IntN(9_000_000),
+		Brand:       pick(g.rnd, brands),
+		Status:      202,
+	}
+}
+
+func (g *Generator) payment(orderUID, currency string, items []domain.Item) domain.Payment {
+	goodsTotal := 0
+	for _, it := range items {
+		goodsTotal += it.TotalPrice
+	}
+	deliveryCost := 100 + g.rnd.IntN(2000)
+	customFee := g.rnd.IntN(3) * 50
+
+	return domain.Payment{
+		Transaction:  orderUID,
+		RequestID:    "",
+		Currency:     currency,
+		Provider:     pick(g.rnd, providers),
+		Amount:       goodsTotal + deliveryCost + customFee,
+		PaymentDT:    g.now.Unix(),
+		Bank:         pick(g.rnd, banks),
+		DeliveryCost: deliveryCost,
+		GoodsTotal:   goodsTotal,
+		CustomFee:    customFee,
+	}
+}
+
+func recalcPayment(order *domain.Order) {
+	goodsTotal := 0
+	for _, it := range order.Items {
+		goodsTotal += it.TotalPrice
+	}
+	order.Payment.GoodsTotal = goodsTotal
+	order.Payment.Amount = goodsTotal + order.Payment.DeliveryCost + order.Payment.CustomFee
+}
+
+func totalPrice(price, sale int) int {
+	return price * (100 - sale) / 100
+}
+
+func pick(rnd *rand.Rand, values []string) string {
+	return values[rnd.IntN(len(values))]
+}
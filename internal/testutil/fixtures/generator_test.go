@@ -0,0 +1,92 @@
+package fixtures
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/app/ingest"
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+func checkOrders(t *testing.T, orders []domain.Order) {
+	t.Helper()
+	decoder := ingest.NewDecoder()
+	seen := map[string]bool{}
+	for _, order := range orders {
+		if err := decoder.Validate(order); err != nil {
+			t.Errorf("order %s: %v", order.OrderUID, err)
+		}
+		if seen[order.OrderUID] {
+			t.Errorf("duplicate order_uid %s", order.OrderUID)
+		}
+		seen[order.OrderUID] = true
+
+		goodsTotal := 0
+		for _, item := range order.Items {
+			goodsTotal += item.TotalPrice
+		}
+		payment := order.Payment
+		if payment.GoodsTotal != goodsTotal ||
+			payment.Amount != payment.GoodsTotal+payment.DeliveryCost+payment.CustomFee {
+			t.Errorf("order %s: inconsistent payment %+v", order.OrderUID, payment)
+		}
+	}
+}
+
+func TestOrdersAreValid(t *testing.T) {
+	checkOrders(t, New(1).Orders(200))
+}
+
+func TestEdgeCasesAreValid(t *testing.T) {
+	orders := EdgeCases()
+	checkOrders(t, orders)
+
+	if len(orders[0].Items) != MaxItems {
+		t.Errorf("max items order has %d items", len(orders[0].Items))
+	}
+	if !reflect.DeepEqual(orders, EdgeCases()) {
+		t.Error("EdgeCases differs between calls")
+	}
+}
+
+func TestSameSeedSameOrders(t *testing.T) {
+	if !reflect.DeepEqual(New(7).Orders(20), New(7).Orders(20)) {
+		t.Error("the same seed produced different orders")
+	}
+	if reflect.DeepEqual(New(7).Orders(20), New(8).Orders(20)) {
+		t.Error("different seeds produced the same orders")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	orders := New(1, WithItemsRange(2, 3), WithStartDate(start)).Orders(50)
+
+	previous := start
+	for i, order := range orders {
+		if n := len(order.Items); n < 2 || n > 3 {
+			t.Errorf("order %d has %d items", i, n)
+		}
+		if i == 0 && order.DateCreated.After(start.Add(time.Hour)) {
+			t.Errorf("first order created at %v, want within an hour of %v", order.DateCreated, start)
+		}
+		if order.DateCreated.Before(previous) {
+			t.Errorf("order %d created before the previous one", i)
+		}
+		previous = order.DateCreated
+	}
+}
+
+func TestDefaultItemsRange(t *testing.T) {
+	for _, order := range New(1, WithItemsRange(0, -1)).Orders(20) {
+		if len(order.Items) != 1 {
+			t.Fatalf("%d items, want 1", len(order.Items))
+		}
+	}
+	for _, order := range New(1).Orders(100) {
+		if n := len(order.Items); n < DefaultItemsMin || n > DefaultItemsMax {
+			t.Fatalf("%d items outside the default range", n)
+		}
+	}
+}
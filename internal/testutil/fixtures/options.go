@@ -0,0 +1,31 @@
+package fixtures
+
+import "time"
+
+type Option interface {
+	Apply(*Generator)
+}
+
+type optionFn func(*Generator)
+
+func (fn optionFn) Apply(g *Generator) {
+	fn(g)
+}
+
+func WithItemsRange(minItems, maxItems int) Option {
+	return optionFn(func(g *Generator) {
+		if minItems < 1 {
+			minItems = 1
+		}
+		if maxItems < minItems {
+			maxItems = minItems
+		}
+		g.itemsMin, g.itemsMax = minItems, maxItems
+	})
+}
+
+func WithStartDate(start time.Time) Option {
+	return optionFn(func(g *Generator) {
+		g.now = start.UTC()
+	})
+}
@@ -0,0 +1,53 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		GetCustomerOrders(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error)
+		GetCustomerSummary(ctx context.Context, customerID string) (*domain.CustomerSummary, error)
+	}
+	summaryCache interface {
+		Get(customerID string) *domain.CustomerSummary
+		Put(summary *domain.CustomerSummary)
+	}
+
+	Usecase struct {
+		repo      repository
+		summaries summaryCache
+	}
+)
+
+func New(repo repository, summaries summaryCache) *Usecase {
+	return &Usecase{
+		repo:      repo,
+		summaries: summaries,
+	}
+}
+
+func (u *Usecase) GetSummary(ctx context.Context, customerID string) (*domain.CustomerSummary, error) {
+	if summary := u.summaries.Get(customerID); summary != nil {
+		return summary, nil
+	}
+
+	summary, err := u.repo.GetCustomerSummary(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetCustomerSummary: %w", err)
+	}
+
+	u.summaries.Put(summary)
+	return summary, nil
+}
+
+func (u *Usecase) GetOrders(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error) {
+	orders, err := u.repo.GetCustomerOrders(ctx, customerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetCustomerOrders: %w", err)
+	}
+	return orders, nil
+}
@@ -0,0 +1,67 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+	memorycustomersummary "github.com/AndrejDubinin/wbtech-l0/internal/infra/cache/memory_customer_summary"
+)
+
+type fakeRepository struct {
+	summaries map[string]*domain.CustomerSummary
+	calls     int
+}
+
+func (r *fakeRepository) GetCustomerOrders(context.Context, string, int, int) ([]*domain.Order, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetCustomerSummary(_ context.Context, customerID string) (*domain.CustomerSummary, error) {
+	r.calls++
+	summary, inMap := r.summaries[customerID]
+	if !inMap {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return summary, nil
+}
+
+func TestGetSummaryCaches(t *testing.T) {
+	repo := &fakeRepository{summaries: map[string]*domain.CustomerSummary{
+		"c-1": {CustomerID: "c-1", Orders: 2},
+	}}
+	summaries := memorycustomersummary.New(10, time.Hour)
+	u := New(repo, summaries)
+
+	for range 2 {
+		summary, err := u.GetSummary(context.Background(), "c-1")
+		if err != nil || summary.Orders != 2 {
+			t.Fatalf("GetSummary %+v, %v", summary, err)
+		}
+	}
+	if repo.calls != 1 {
+		t.Errorf("repository called %d times, want 1", repo.calls)
+	}
+
+	summaries.Delete("c-1")
+	repo.summaries["c-1"] = &domain.CustomerSummary{CustomerID: "c-1", Orders: 3}
+	if summary, err := u.GetSummary(context.Background(), "c-1"); err != nil || summary.Orders != 3 {
+		t.Errorf("GetSummary after Delete %+v, %v", summary, err)
+	}
+}
+
+func TestGetSummaryNotFound(t *testing.T) {
+	repo := &fakeRepository{}
+	u := New(repo, memorycustomersummary.New(10, time.Hour))
+
+	for range 2 {
+		if _, err := u.GetSummary(context.Background(), "c-missing"); !errors.Is(err, domain.ErrCustomerNotFound) {
+			t.Fatalf("err %v, want ErrCustomerNotFound", err)
+		}
+	}
+	if repo.calls != 2 {
+		t.Errorf("repository called %d times, want a miss not to be cached", repo.calls)
+	}
+}
@@ -0,0 +1,89 @@
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+const purgeRequester = "retention"
+
+type (
+	repository interface {
+		AnonymizeCustomer(ctx context.Context, customerID, token string, request domain.ErasureRequest,
+		) (*domain.Erasure, error)
+		SoftDeleteOrder(ctx context.Context, orderUID string, request domain.ErasureRequest) (*domain.Erasure, error)
+		PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time, request domain.ErasureRequest,
+		) (*domain.Erasure, error)
+	}
+	cache interface {
+		Delete(orderUID string)
+	}
+	summaryCache interface {
+		Delete(customerID string)
+	}
+	feed interface {
+		Forget(orderUIDs ...string)
+	}
+
+	Usecase struct {
+		repo      repository
+		cache     cache
+		summaries summaryCache
+		feed      feed
+		retention time.Duration
+	}
+)
+
+func New(repo repository, cache cache, summaries summaryCache, feed feed, retention time.Duration) *Usecase {
+	return &Usecase{
+		repo:      repo,
+		cache:     cache,
+		summaries: summaries,
+		feed:      feed,
+		retention: retention,
+	}
+}
+
+func (u *Usecase) AnonymizeCustomer(ctx context.Context, customerID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	erasure, err := u.repo.AnonymizeCustomer(ctx, customerID, domain.NewAnonymousToken(), request)
+	if err != nil {
+		return nil, fmt.Errorf("repo.AnonymizeCustomer: %w", err)
+	}
+
+	u.evict(erasure)
+	return erasure, nil
+}
+
+func (u *Usecase) DeleteOrder(ctx context.Context, orderUID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	erasure, err := u.repo.SoftDeleteOrder(ctx, orderUID, request)
+	if err != nil {
+		return nil, fmt.Errorf("repo.SoftDeleteOrder: %w", err)
+	}
+
+	u.evict(erasure)
+	return erasure, nil
+}
+
+func (u *Usecase) Purge(ctx context.Context) (*domain.Erasure, error) {
+	erasure, err := u.repo.PurgeDeletedOrders(ctx, time.Now().Add(-u.retention), domain.ErasureRequest{
+		RequestedBy: purgeRequester,
+		Reason:      fmt.Sprintf("deleted more than %s ago", u.retention),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.PurgeDeletedOrders: %w", err)
+	}
+	return erasure, nil
+}
+
+func (u *Usecase) evict(erasure *domain.Erasure) {
+	for _, orderUID := range erasure.OrderUIDs {
+		u.cache.Delete(orderUID)
+	}
+	u.feed.Forget(erasure.OrderUIDs...)
+	u.summaries.Delete(erasure.CustomerID)
+}
@@ -0,0 +1,129 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	fakeRepository struct {
+		token         string
+		deletedBefore time.Time
+		request       domain.ErasureRequest
+		err           error
+	}
+	fakeCache struct {
+		deleted []string
+	}
+	fakeFeed struct {
+		forgotten []string
+	}
+)
+
+func (r *fakeRepository) AnonymizeCustomer(_ context.Context, customerID, token string,
+	request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	r.token, r.request = token, request
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &domain.Erasure{Action: domain.ErasureAnonymize, Subject: token, OrderUIDs: []string{"a", "b"},
+		CustomerID: customerID}, nil
+}
+
+func (r *fakeRepository) SoftDeleteOrder(_ context.Context, orderUID string, request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	r.request = request
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &domain.Erasure{Action: domain.ErasureDelete, Subject: orderUID, OrderUIDs: []string{orderUID},
+		CustomerID: "c-1"}, nil
+}
+
+func (r *fakeRepository) PurgeDeletedOrders(_ context.Context, deletedBefore time.Time,
+	request domain.ErasureRequest,
+) (*domain.Erasure, error) {
+	r.deletedBefore, r.request = deletedBefore, request
+	return &domain.Erasure{Action: domain.ErasurePurge}, r.err
+}
+
+func (c *fakeCache) Delete(key string) {
+	c.deleted = append(c.deleted, key)
+}
+
+func (f *fakeFeed) Forget(orderUIDs ...string) {
+	f.forgotten = append(f.forgotten, orderUIDs...)
+}
+
+func TestAnonymizeCustomerEvicts(t *testing.T) {
+	repo, orders, summaries, feed := &fakeRepository{}, &fakeCache{}, &fakeCache{}, &fakeFeed{}
+	u := New(repo, orders, summaries, feed, time.Hour)
+
+	request := domain.ErasureRequest{RequestedBy: "api_key:support", Reason: "ticket"}
+	erasure, err := u.AnonymizeCustomer(context.Background(), "c-1", request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(repo.token, "anon-") || erasure.Subject != repo.token || repo.request != request {
+		t.Errorf("token %q, request %+v", repo.token, repo.request)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(orders.deleted, want) || !slices.Equal(feed.forgotten, want) {
+		t.Errorf("evicted %v, forgotten %v, want %v", orders.deleted, feed.forgotten, want)
+	}
+	if !slices.Equal(summaries.deleted, []string{"c-1"}) {
+		t.Errorf("summaries evicted %v", summaries.deleted)
+	}
+}
+
+func TestDeleteOrderEvicts(t *testing.T) {
+	orders, summaries, feed := &fakeCache{}, &fakeCache{}, &fakeFeed{}
+	u := New(&fakeRepository{}, orders, summaries, feed, time.Hour)
+
+	if _, err := u.DeleteOrder(context.Background(), "o-1", domain.ErasureRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(orders.deleted, []string{"o-1"}) || !slices.Equal(feed.forgotten, []string{"o-1"}) ||
+		!slices.Equal(summaries.deleted, []string{"c-1"}) {
+		t.Errorf("evicted %v, forgotten %v, summaries %v", orders.deleted, feed.forgotten, summaries.deleted)
+	}
+}
+
+func TestErasureErrorsEvictNothing(t *testing.T) {
+	orders, feed := &fakeCache{}, &fakeFeed{}
+	u := New(&fakeRepository{err: domain.ErrOrderNotFound}, orders, &fakeCache{}, feed, time.Hour)
+
+	if _, err := u.DeleteOrder(context.Background(), "o-1", domain.ErasureRequest{}); !errors.Is(err,
+		domain.ErrOrderNotFound) {
+		t.Errorf("err %v, want ErrOrderNotFound", err)
+	}
+	if _, err := u.AnonymizeCustomer(context.Background(), "c-1", domain.ErasureRequest{}); !errors.Is(err,
+		domain.ErrOrderNotFound) {
+		t.Errorf("err %v, want ErrOrderNotFound", err)
+	}
+	if len(orders.deleted) != 0 || len(feed.forgotten) != 0 {
+		t.Errorf("evicted %v, forgotten %v", orders.deleted, feed.forgotten)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	repo := &fakeRepository{}
+	u := New(repo, &fakeCache{}, &fakeCache{}, &fakeFeed{}, time.Hour)
+
+	from := time.Now().Add(-time.Hour)
+	if _, err := u.Purge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if to := time.Now().Add(-time.Hour); repo.deletedBefore.Before(from) || repo.deletedBefore.After(to) {
+		t.Errorf("deletedBefore %v, want an hour ago", repo.deletedBefore)
+	}
+	if repo.request.RequestedBy != purgeRequester {
+		t.Errorf("requested by %q", repo.request.RequestedBy)
+	}
+}
@@ -14,17 +14,27 @@ type (
 	cache interface {
 		Put(order *domain.Order)
 	}
+	publisher interface {
+		Publish(order *domain.Order)
+	}
+	summaryCache interface {
+		Delete(customerID string)
+	}
 
 	Usecase struct {
-		repo  repository
-		cache cache
+		repo      repository
+		cache     cache
+		summaries summaryCache
+		publisher publisher
 	}
 )
 
-func New(repo repository, cache cache) *Usecase {
+func New(repo repository, cache cache, summaries summaryCache, publisher publisher) *Usecase {
 	return &Usecase{
-		repo:  repo,
-		cache: cache,
+		repo:      repo,
+		cache:     cache,
+		summaries: summaries,
+		publisher: publisher,
 	}
 }
 
@@ -35,6 +45,8 @@ func (u *Usecase) AddOrder(ctx context.Context, order domain.Order) error {
 	}
 
 	u.cache.Put(&order)
+	u.summaries.Delete(order.CustomerID)
+	u.publisher.Publish(&order)
 
 	return nil
 }
@@ -0,0 +1,74 @@
+package batchget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		GetOrdersByUIDs(ctx context.Context, orderUIDs []string) (map[string]*domain.Order, error)
+	}
+	cache interface {
+		Get(orderUID string) *domain.Order
+	}
+
+	Usecase struct {
+		repo  repository
+		cache cache
+	}
+)
+
+func New(repo repository, cache cache) *Usecase {
+	return &Usecase{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// GetOrders returns the found orders in request order and the missing UIDs.
+func (u *Usecase) GetOrders(ctx context.Context, orderUIDs []string) ([]*domain.Order, []string, error) {
+	found := make(map[string]*domain.Order, len(orderUIDs))
+	toLoad := make([]string, 0, len(orderUIDs))
+
+	for _, orderUID := range orderUIDs {
+		if _, inMap := found[orderUID]; inMap {
+			continue
+		}
+		order := u.cache.Get(orderUID)
+		found[orderUID] = order
+		if order == nil {
+			toLoad = append(toLoad, orderUID)
+		}
+	}
+
+	if len(toLoad) > 0 {
+		loaded, err := u.repo.GetOrdersByUIDs(ctx, toLoad)
+		if err != nil {
+			return nil, nil, fmt.Errorf("repo.GetOrdersByUIDs: %w", err)
+		}
+		for orderUID, order := range loaded {
+			found[orderUID] = order
+		}
+	}
+
+	orders := make([]*domain.Order, 0, len(found))
+	missing := []string{}
+	for _, orderUID := range orderUIDs {
+		order, inMap := found[orderUID]
+		if !inMap {
+			continue // duplicate UID, already handled
+		}
+		delete(found, orderUID)
+
+		if order == nil {
+			missing = append(missing, orderUID)
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, missing, nil
+}
@@ -0,0 +1,98 @@
+package batchget
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	fakeRepo struct {
+		orders map[string]*domain.Order
+		calls  [][]string
+		err    error
+	}
+	fakeCache map[string]*domain.Order
+)
+
+func (r *fakeRepo) GetOrdersByUIDs(_ context.Context, orderUIDs []string) (map[string]*domain.Order, error) {
+	r.calls = append(r.calls, orderUIDs)
+	if r.err != nil {
+		return nil, r.err
+	}
+	found := map[string]*domain.Order{}
+	for _, uid := range orderUIDs {
+		if order, inMap := r.orders[uid]; inMap {
+			found[uid] = order
+		}
+	}
+	return found, nil
+}
+
+func (c fakeCache) Get(orderUID string) *domain.Order {
+	return c[orderUID]
+}
+
+func uids(orders []*domain.Order) []string {
+	var result []string
+	for _, order := range orders {
+		result = append(result, order.OrderUID)
+	}
+	return result
+}
+
+func TestGetOrders(t *testing.T) {
+	repo := &fakeRepo{orders: map[string]*domain.Order{
+		"stored-1": {OrderUID: "stored-1"},
+		"stored-2": {OrderUID: "stored-2"},
+	}}
+	cache := fakeCache{"cached-1": {OrderUID: "cached-1"}}
+
+	orders, missing, err := New(repo, cache).GetOrders(context.Background(),
+		[]string{"stored-2", "cached-1", "missing-1", "stored-1", "stored-2", "missing-1"})
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+
+	if got, want := uids(orders), []string{"stored-2", "cached-1", "stored-1"}; !slices.Equal(got, want) {
+		t.Errorf("orders %v, want %v in request order without duplicates", got, want)
+	}
+	if want := []string{"missing-1"}; !slices.Equal(missing, want) {
+		t.Errorf("missing %v, want %v", missing, want)
+	}
+	if len(repo.calls) != 1 {
+		t.Fatalf("%d repository calls, want 1", len(repo.calls))
+	}
+	if want := []string{"stored-2", "missing-1", "stored-1"}; !slices.Equal(repo.calls[0], want) {
+		t.Errorf("loaded %v, want %v", repo.calls[0], want)
+	}
+}
+
+func TestGetOrdersAllCached(t *testing.T) {
+	repo := &fakeRepo{}
+	orders, missing, err := New(repo, fakeCache{"cached-1": {OrderUID: "cached-1"}}).GetOrders(
+		context.Background(), []string{"cached-1"})
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+	if len(orders) != 1 || len(missing) != 0 {
+		t.Errorf("orders %v, missing %v", uids(orders), missing)
+	}
+	if missing == nil {
+		t.Error("missing must be an empty list, not null")
+	}
+	if len(repo.calls) != 0 {
+		t.Errorf("repository called for cached orders: %v", repo.calls)
+	}
+}
+
+func TestGetOrdersRepositoryError(t *testing.T) {
+	errDB := errors.New("db is down")
+	_, _, err := New(&fakeRepo{err: errDB}, fakeCache{}).GetOrders(context.Background(), []string{"order-1"})
+	if !errors.Is(err, errDB) {
+		t.Fatalf("error %v, want %v", err, errDB)
+	}
+}
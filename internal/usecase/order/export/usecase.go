@@ -0,0 +1,44 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		ExportOrders(ctx context.Context, filter domain.OrderFilter, fn func(order *domain.Order) error) error
+	}
+
+	Usecase struct {
+		repo repository
+	}
+)
+
+func New(repo repository) *Usecase {
+	return &Usecase{
+		repo: repo,
+	}
+}
+
+func (u *Usecase) ExportOrders(ctx context.Context, filter domain.OrderFilter, w domain.OrderWriter) (int, error) {
+	count := 0
+	err := u.repo.ExportOrders(ctx, filter, func(order *domain.Order) error {
+		if err := w.Write(order); err != nil {
+			return fmt.Errorf("writer.Write: %w", err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, fmt.Errorf("repo.ExportOrders: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return count, fmt.Errorf("writer.Flush: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	fakeRepo struct {
+		orders []domain.Order
+		err    error
+		filter domain.OrderFilter
+	}
+	fakeWriter struct {
+		written  []string
+		flushed  bool
+		writeErr error
+	}
+)
+
+func (r *fakeRepo) ExportOrders(_ context.Context, filter domain.OrderFilter, fn func(order *domain.Order) error) error {
+	r.filter = filter
+	for i := range r.orders {
+		if err := fn(&r.orders[i]); err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+func (w *fakeWriter) Write(order *domain.Order) error {
+	if w.writeErr != nil {
+		return w.writeErr
+	}
+	w.written = append(w.written, order.OrderUID)
+	return nil
+}
+
+func (w *fakeWriter) Flush() error {
+	w.flushed = true
+	return nil
+}
+
+func TestExportOrders(t *testing.T) {
+	repo := &fakeRepo{orders: []domain.Order{{OrderUID: "a"}, {OrderUID: "b"}}}
+	writer := &fakeWriter{}
+	filter := domain.OrderFilter{CustomerID: "c-1"}
+
+	count, err := New(repo).ExportOrders(context.Background(), filter, writer)
+	if err != nil {
+		t.Fatalf("ExportOrders: %v", err)
+	}
+	if count != 2 || len(writer.written) != 2 || !writer.flushed {
+		t.Errorf("count %d, written %v, flushed %v", count, writer.written, writer.flushed)
+	}
+	if repo.filter != filter {
+		t.Errorf("filter %+v, want %+v", repo.filter, filter)
+	}
+}
+
+func TestExportOrdersRepoError(t *testing.T) {
+	repo := &fakeRepo{orders: []domain.Order{{OrderUID: "a"}}, err: errors.New("db down")}
+	writer := &fakeWriter{}
+
+	count, err := New(repo).ExportOrders(context.Background(), domain.OrderFilter{}, writer)
+	if !errors.Is(err, repo.err) {
+		t.Fatalf("error %v, want %v", err, repo.err)
+	}
+	if count != 1 || writer.flushed {
+		t.Errorf("count %d, flushed %v; want 1 written and no flush", count, writer.flushed)
+	}
+}
+
+func TestExportOrdersWriteError(t *testing.T) {
+	repo := &fakeRepo{orders: []domain.Order{{OrderUID: "a"}, {OrderUID: "b"}}}
+	writer := &fakeWriter{writeErr: errors.New("broken pipe")}
+
+	count, err := New(repo).ExportOrders(context.Background(), domain.OrderFilter{}, writer)
+	if !errors.Is(err, writer.writeErr) || count != 0 {
+		t.Fatalf("count %d, error %v", count, err)
+	}
+}
@@ -0,0 +1,51 @@
+package lookup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		GetOrdersByKey(ctx context.Context, key domain.OrderKey, limit int) ([]*domain.Order, error)
+	}
+	cache interface {
+		GetByKey(key domain.OrderKey) ([]*domain.Order, bool)
+		PutByKey(key domain.OrderKey, orders []*domain.Order)
+	}
+
+	Usecase struct {
+		repo  repository
+		cache cache
+	}
+)
+
+func New(repo repository, cache cache) *Usecase {
+	return &Usecase{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// GetOrdersByKey returns up to limit orders with key newest first.
+func (u *Usecase) GetOrdersByKey(ctx context.Context, key domain.OrderKey, limit int) ([]*domain.Order, error) {
+	if orders, ok := u.cache.GetByKey(key); ok {
+		return orders[:min(len(orders), limit)], nil
+	}
+
+	orders, err := u.repo.GetOrdersByKey(ctx, key, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetOrdersByKey: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if len(orders) > limit {
+		return orders[:limit], nil
+	}
+	u.cache.PutByKey(key, orders)
+	return orders, nil
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		GetOrdersParts(ctx context.Context, orderUIDs []string, parts domain.OrderParts,
+		) (map[string]*domain.Order, error)
+		ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+			limit int, parts domain.OrderParts) ([]*domain.Order, error)
+		GetOrderItems(ctx context.Context, orderUID string, limit, offset int) ([]domain.Item, int, error)
+	}
+	cache interface {
+		Get(orderUID string) *domain.Order
+	}
+
+	Usecase struct {
+		repo  repository
+		cache cache
+	}
+)
+
+func New(repo repository, cache cache) *Usecase {
+	return &Usecase{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// GetOrder returns a cached order complete.
+func (u *Usecase) GetOrder(ctx context.Context, orderUID string, parts domain.OrderParts) (*domain.Order, error) {
+	if order := u.cache.Get(orderUID); order != nil {
+		return order, nil
+	}
+
+	found, err := u.repo.GetOrdersParts(ctx, []string{orderUID}, parts)
+	if err != nil {
+		return nil, fmt.Errorf("repo.GetOrdersParts: %w", err)
+	}
+	order, inMap := found[orderUID]
+	if !inMap {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+func (u *Usecase) ListOrders(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor,
+	limit int, parts domain.OrderParts,
+) ([]*domain.Order, error) {
+	orders, err := u.repo.ListOrders(ctx, filter, after, limit, parts)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListOrders: %w", err)
+	}
+	return orders, nil
+}
+
+func (u *Usecase) GetOrderItems(ctx context.Context, orderUID string, limit, offset int,
+) ([]domain.Item, int, error) {
+	if order := u.cache.Get(orderUID); order != nil {
+		total := len(order.Items)
+		start := min(offset, total)
+		return order.Items[start:min(start+limit, total)], total, nil
+	}
+
+	items, total, err := u.repo.GetOrderItems(ctx, orderUID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repo.GetOrderItems: %w", err)
+	}
+	return items, total, nil
+}
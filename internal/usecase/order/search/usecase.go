@@ -0,0 +1,32 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		SearchOrders(ctx context.Context, search domain.OrderSearch) ([]domain.OrderHit, error)
+	}
+
+	Usecase struct {
+		repo repository
+	}
+)
+
+func New(repo repository) *Usecase {
+	return &Usecase{
+		repo: repo,
+	}
+}
+
+func (u *Usecase) SearchOrders(ctx context.Context, search domain.OrderSearch) ([]domain.OrderHit, error) {
+	hits, err := u.repo.SearchOrders(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("repo.SearchOrders: %w", err)
+	}
+	return hits, nil
+}
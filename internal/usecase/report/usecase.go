@@ -0,0 +1,45 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AndrejDubinin/wbtech-l0/internal/domain"
+)
+
+type (
+	repository interface {
+		Report(ctx context.Context, kind domain.ReportKind, query domain.ReportQuery) (*domain.Report, error)
+		RefreshReports(ctx context.Context) error
+	}
+
+	Usecase struct {
+		repo        repository
+		precomputed bool
+	}
+)
+
+// precomputed tells that the report views are refreshed periodically.
+func New(repo repository, precomputed bool) *Usecase {
+	return &Usecase{
+		repo:        repo,
+		precomputed: precomputed,
+	}
+}
+
+func (u *Usecase) Report(ctx context.Context, kind domain.ReportKind, query domain.ReportQuery,
+) (*domain.Report, error) {
+	query.Precomputed = u.precomputed
+	report, err := u.repo.Report(ctx, kind, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo.Report: %w", err)
+	}
+	return report, nil
+}
+
+func (u *Usecase) Refresh(ctx context.Context) error {
+	if err := u.repo.RefreshReports(ctx); err != nil {
+		return fmt.Errorf("repo.RefreshReports: %w", err)
+	}
+	return nil
+}
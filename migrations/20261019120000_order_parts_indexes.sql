@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS orders_date_created_idx ON orders (date_created DESC, order_uid DESC);
+CREATE INDEX IF NOT EXISTS delivery_order_uid_idx ON delivery (order_uid);
+CREATE INDEX IF NOT EXISTS payment_order_uid_idx ON payment (order_uid);
+CREATE INDEX IF NOT EXISTS items_order_uid_idx ON items (order_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS items_order_uid_idx;
+DROP INDEX IF EXISTS payment_order_uid_idx;
+DROP INDEX IF EXISTS delivery_order_uid_idx;
+DROP INDEX IF EXISTS orders_date_created_idx;
+-- +goose StatementEnd
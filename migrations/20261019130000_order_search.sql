@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- The expressions must stay identical to the ones in the search query of
+-- the order repository, otherwise the indexes are not used.
+CREATE INDEX IF NOT EXISTS delivery_search_idx ON delivery USING GIN (
+  to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, '') || ' ' || coalesce(phone, '') || ' ' ||
+    coalesce(city, '') || ' ' || coalesce(address, ''))
+);
+CREATE INDEX IF NOT EXISTS items_search_idx ON items USING GIN (
+  to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(brand, ''))
+);
+CREATE INDEX IF NOT EXISTS delivery_phone_trgm_idx ON delivery USING GIN (
+  regexp_replace(phone, '\D', '', 'g') gin_trgm_ops
+);
+CREATE INDEX IF NOT EXISTS orders_track_number_trgm_idx ON orders USING GIN (track_number gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_track_number_trgm_idx;
+DROP INDEX IF EXISTS delivery_phone_trgm_idx;
+DROP INDEX IF EXISTS items_search_idx;
+DROP INDEX IF EXISTS delivery_search_idx;
+-- +goose StatementEnd
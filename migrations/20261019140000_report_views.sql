@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Order and item facts in 15-minute buckets: every time zone offset is a
+-- multiple of 15 minutes, so calendar days of any zone are whole buckets.
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_order_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(p.provider, '') AS provider,
+  coalesce(p.currency, '') AS currency,
+  count(*) AS orders,
+  sum(p.amount) AS revenue,
+  sum(coalesce(i.items, 0)) AS items
+FROM orders o
+INNER JOIN payment p ON p.order_uid = o.order_uid
+LEFT JOIN (SELECT order_uid, count(*) AS items FROM items GROUP BY order_uid) i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX IF NOT EXISTS report_order_facts_key ON report_order_facts
+  (bucket, delivery_service, provider, currency);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_brand_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(i.brand, '') AS brand,
+  count(*) AS items
+FROM orders o
+INNER JOIN items i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX IF NOT EXISTS report_brand_facts_key ON report_brand_facts (bucket, delivery_service, brand);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS report_brand_facts;
+DROP MATERIALIZED VIEW IF EXISTS report_order_facts;
+-- +goose StatementEnd
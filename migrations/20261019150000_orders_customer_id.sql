@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS orders_customer_id_idx ON orders (customer_id, date_created DESC, order_uid DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_customer_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS orders_track_number_idx ON orders (track_number);
+CREATE INDEX IF NOT EXISTS items_track_number_idx ON items (track_number);
+CREATE INDEX IF NOT EXISTS payment_transaction_idx ON payment (transaction);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS payment_transaction_idx;
+DROP INDEX IF EXISTS items_track_number_idx;
+DROP INDEX IF EXISTS orders_track_number_idx;
+-- +goose StatementEnd
@@ -0,0 +1,89 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS orders_deleted_at_idx ON orders (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Who asked to erase what. Anonymised customers are recorded by their new
+-- token only, the original ID must not survive the erasure.
+CREATE TABLE IF NOT EXISTS erasure_audit (
+  id BIGSERIAL PRIMARY KEY,
+  action TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  requested_by TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  orders INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The report views skip deleted orders like every other read.
+DROP MATERIALIZED VIEW IF EXISTS report_order_facts;
+CREATE MATERIALIZED VIEW report_order_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(p.provider, '') AS provider,
+  coalesce(p.currency, '') AS currency,
+  count(*) AS orders,
+  sum(p.amount) AS revenue,
+  sum(coalesce(i.items, 0)) AS items
+FROM orders o
+INNER JOIN payment p ON p.order_uid = o.order_uid
+LEFT JOIN (SELECT order_uid, count(*) AS items FROM items GROUP BY order_uid) i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL AND o.deleted_at IS NULL
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX report_order_facts_key ON report_order_facts (bucket, delivery_service, provider, currency);
+
+DROP MATERIALIZED VIEW IF EXISTS report_brand_facts;
+CREATE MATERIALIZED VIEW report_brand_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(i.brand, '') AS brand,
+  count(*) AS items
+FROM orders o
+INNER JOIN items i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL AND o.deleted_at IS NULL
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX report_brand_facts_key ON report_brand_facts (bucket, delivery_service, brand);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS report_order_facts;
+CREATE MATERIALIZED VIEW report_order_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(p.provider, '') AS provider,
+  coalesce(p.currency, '') AS currency,
+  count(*) AS orders,
+  sum(p.amount) AS revenue,
+  sum(coalesce(i.items, 0)) AS items
+FROM orders o
+INNER JOIN payment p ON p.order_uid = o.order_uid
+LEFT JOIN (SELECT order_uid, count(*) AS items FROM items GROUP BY order_uid) i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX report_order_facts_key ON report_order_facts (bucket, delivery_service, provider, currency);
+
+DROP MATERIALIZED VIEW IF EXISTS report_brand_facts;
+CREATE MATERIALIZED VIEW report_brand_facts AS
+SELECT
+  date_bin('15 minutes', o.date_created, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS bucket,
+  coalesce(o.delivery_service, '') AS delivery_service,
+  coalesce(i.brand, '') AS brand,
+  count(*) AS items
+FROM orders o
+INNER JOIN items i ON i.order_uid = o.order_uid
+WHERE o.date_created IS NOT NULL
+GROUP BY 1, 2, 3;
+
+CREATE UNIQUE INDEX report_brand_facts_key ON report_brand_facts (bucket, delivery_service, brand);
+
+DROP TABLE IF EXISTS erasure_audit;
+DROP INDEX IF EXISTS orders_deleted_at_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Searches without access to personal data match the city only.
+CREATE INDEX IF NOT EXISTS delivery_public_search_idx ON delivery USING GIN (
+  to_tsvector('simple', coalesce(city, ''))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS delivery_public_search_idx;
+-- +goose StatementEnd
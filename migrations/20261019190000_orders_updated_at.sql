@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set when an order changes after ingestion, like on anonymisation.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,194 @@
+"use strict";
+
+const RECENT_KEY = "wbtech-l0:recent-orders";
+const RECENT_LIMIT = 10;
+const API_KEY_KEY = "wbtech-l0:api-key";
+
+const errorMessages = {
+	400: "Order ID has an invalid format: use 8-64 latin letters, digits, '_' or '-'.",
+	401: "Authentication required: enter a valid API key.",
+	403: "Your API key is not allowed to read orders.",
+	404: "Order not found. Check the ID and try again.",
+};
+
+const els = {
+	form: document.getElementById("lookup"),
+	input: document.getElementById("orderId"),
+	apiKey: document.getElementById("apiKey"),
+	error: document.getElementById("error"),
+	result: document.getElementById("result"),
+	recent: document.getElementById("recent"),
+	recentList: document.getElementById("recentList"),
+	recentClear: document.getElementById("recentClear"),
+	template: document.getElementById("orderTemplate"),
+};
+
+function loadRecent() {
+	try {
+		const ids = JSON.parse(localStorage.getItem(RECENT_KEY) || "[]");
+		return Array.isArray(ids) ? ids : [];
+	} catch (e) {
+		return [];
+	}
+}
+
+function saveRecent(ids) {
+	try {
+		localStorage.setItem(RECENT_KEY, JSON.stringify(ids));
+	} catch (e) {
+		// storage may be unavailable (private mode, quota), recent list is optional
+	}
+}
+
+function rememberOrder(id) {
+	const ids = loadRecent().filter((v) => v !== id);
+	ids.unshift(id);
+	saveRecent(ids.slice(0, RECENT_LIMIT));
+	renderRecent();
+}
+
+function renderRecent() {
+	const ids = loadRecent();
+	els.recentList.replaceChildren();
+	els.recent.hidden = ids.length === 0;
+
+	for (const id of ids) {
+		const link = document.createElement("a");
+		link.href = "#" + encodeURIComponent(id);
+		link.textContent = id;
+		link.addEventListener("click", (e) => {
+			e.preventDefault();
+			els.input.value = id;
+			getOrder(id);
+		});
+
+		const li = document.createElement("li");
+		li.appendChild(link);
+		els.recentList.appendChild(li);
+	}
+}
+
+function formatMoney(value, currency) {
+	try {
+		return new Intl.NumberFormat(undefined, { style: "currency", currency }).format(value);
+	} catch (e) {
+		return `${value} ${currency}`;
+	}
+}
+
+function formatDate(value) {
+	const date = new Date(value);
+	return isNaN(date) ? String(value) : date.toLocaleString();
+}
+
+function showError(message) {
+	els.result.hidden = true;
+	els.error.textContent = message;
+	els.error.hidden = false;
+}
+
+function renderOrder(order) {
+	const view = els.template.content.cloneNode(true);
+	const currency = order.payment.currency;
+	const delivery = order.delivery;
+	const itemsTotal = order.items.reduce((sum, it) => sum + it.total_price, 0);
+
+	const values = {
+		order_uid: order.order_uid,
+		track_number: order.track_number,
+		entry: order.entry,
+		date_created: formatDate(order.date_created),
+		locale: order.locale,
+		delivery_service: order.delivery_service,
+		customer_id: order.customer_id,
+		"delivery.name": delivery.name,
+		"delivery.phone": delivery.phone,
+		"delivery.email": delivery.email,
+		"delivery.full_address": [delivery.zip, delivery.region, delivery.city, delivery.address]
+			.filter(Boolean)
+			.join(", "),
+		"payment.transaction": order.payment.transaction,
+		"payment.provider": order.payment.provider,
+		"payment.bank": order.payment.bank,
+		"payment.payment_dt": formatDate(order.payment.payment_dt * 1000),
+		"payment.goods_total": formatMoney(order.payment.goods_total, currency),
+		"payment.delivery_cost": formatMoney(order.payment.delivery_cost, currency),
+		"payment.custom_fee": formatMoney(order.payment.custom_fee, currency),
+		"payment.amount": formatMoney(order.payment.amount, currency),
+		items_total: formatMoney(itemsTotal, currency),
+	};
+
+	for (const [field, value] of Object.entries(values)) {
+		view.querySelector(`[data-field="${field}"]`).textContent = value;
+	}
+
+	const tbody = view.querySelector('[data-field="items"]');
+	for (const it of order.items) {
+		const row = document.createElement("tr");
+		for (const value of [
+			it.name,
+			it.brand,
+			it.size,
+			formatMoney(it.price, currency),
+			it.sale + "%",
+			formatMoney(it.total_price, currency),
+		]) {
+			const td = document.createElement("td");
+			td.textContent = value;
+			row.appendChild(td);
+		}
+		tbody.appendChild(row);
+	}
+
+	els.error.hidden = true;
+	els.result.replaceChildren(view);
+	els.result.hidden = false;
+}
+
+async function getOrder(id) {
+	if (!id) return;
+
+	let res;
+	try {
+		const headers = { Accept: "application/json" };
+		const apiKey = els.apiKey.value.trim();
+		if (apiKey) headers["X-API-Key"] = apiKey;
+
+		res = await fetch(`${window.location.origin}/order/${encodeURIComponent(id)}`, { headers });
+	} catch (e) {
+		showError("Service is unreachable. Please try again later.");
+		return;
+	}
+
+	if (!res.ok) {
+		showError(errorMessages[res.status] || `Unexpected error (HTTP ${res.status}). Please try again later.`);
+		return;
+	}
+
+	renderOrder(await res.json());
+	rememberOrder(id);
+}
+
+els.form.addEventListener("submit", (e) => {
+	e.preventDefault();
+	getOrder(els.input.value.trim());
+});
+
+els.recentClear.addEventListener("click", (e) => {
+	e.preventDefault();
+	saveRecent([]);
+	renderRecent();
+});
+
+els.apiKey.value = sessionStorage.getItem(API_KEY_KEY) || "";
+els.apiKey.addEventListener("change", () => {
+	sessionStorage.setItem(API_KEY_KEY, els.apiKey.value.trim());
+});
+
+renderRecent();
+
+if (window.location.hash.length > 1) {
+	const id = decodeURIComponent(window.location.hash.slice(1));
+	els.input.value = id;
+	getOrder(id);
+}
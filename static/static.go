@@ -0,0 +1,8 @@
+package static
+
+import "embed"
+
+// Frontend contains the order viewer UI served by the application itself.
+//
+//go:embed frontend
+var Frontend embed.FS